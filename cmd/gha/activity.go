@@ -0,0 +1,327 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shizhMSFT/gha/pkg/analysis"
+	"github.com/shizhMSFT/gha/pkg/container/set"
+	"github.com/shizhMSFT/gha/pkg/github"
+	"github.com/shizhMSFT/gha/pkg/identity"
+	"github.com/shizhMSFT/gha/pkg/markdown"
+	"github.com/urfave/cli/v3"
+	"golang.org/x/exp/maps"
+)
+
+// heatColors are the ANSI 256 colors of the heat levels in terminal
+// heatmaps, preceded by the color of no activity.
+var heatColors = []int{236, 22, 28, 34, 46}
+
+var weekdays = []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
+
+var activityCommand = &cli.Command{
+	Name:      "activity",
+	Usage:     "analyze activities by day of week and hour of day",
+	ArgsUsage: "<issue_snapshot> [...]",
+	Aliases:   []string{"a"},
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:     "ago",
+			Usage:    "only include activities that are at least `DAYS` old",
+			OnlyOnce: true,
+		},
+		&cli.TimestampFlag{
+			Name:     "start-date",
+			Usage:    "only include activities after `DATE`",
+			Config:   cli.TimestampConfig{Layout: time.DateOnly},
+			OnlyOnce: true,
+		},
+		&cli.TimestampFlag{
+			Name:     "end-date",
+			Usage:    "only include activities before `DATE`",
+			Config:   cli.TimestampConfig{Layout: time.DateOnly},
+			OnlyOnce: true,
+		},
+		&cli.StringSliceFlag{
+			Name:  "comments",
+			Usage: "specify issue comment `SNAPSHOT` files in the same order as the issue snapshots",
+		},
+		&cli.StringSliceFlag{
+			Name:  "reviews",
+			Usage: "specify review `SNAPSHOT` files in the same order as the issue snapshots",
+		},
+		&cli.StringFlag{
+			Name:     "tz",
+			Usage:    "bucket activities in the IANA time `ZONE`, such as America/Los_Angeles",
+			Value:    "UTC",
+			OnlyOnce: true,
+		},
+		&cli.StringFlag{
+			Name:     "identities",
+			Usage:    "bucket activities of people in their time zones in an identity `FILE`",
+			OnlyOnce: true,
+		},
+		&cli.StringFlag{
+			Name:     "maintainers",
+			Usage:    "only count first responses by maintainers listed in a CODEOWNERS, OWNERS or MAINTAINERS file",
+			OnlyOnce: true,
+		},
+		&cli.StringFlag{
+			Name:     "working-hours",
+			Usage:    "working hours of responders on weekdays in the format of `START-END`",
+			Value:    "9-17",
+			OnlyOnce: true,
+		},
+		&cli.StringFlag{
+			Name:     "format",
+			Usage:    "output format of heatmaps in `{markdown, terminal}`",
+			Value:    "markdown",
+			OnlyOnce: true,
+		},
+		botPatternFlag(),
+	},
+	Action: runActivity,
+}
+
+func runActivity(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return errors.New("no snapshot files specified")
+	}
+
+	// parse flags
+	var opts analysis.SummarizeActivityOptions
+	if ago := ctx.Int("ago"); ago > 0 {
+		opts.TimeFrame.Start = time.Now().UTC().AddDate(0, 0, int(-ago))
+	}
+	if date := ctx.Value("start-date").(time.Time); !date.IsZero() {
+		opts.TimeFrame.Start = date
+	}
+	if date := ctx.Value("end-date").(time.Time); !date.IsZero() {
+		opts.TimeFrame.End = date
+	}
+	commentPaths := ctx.StringSlice("comments")
+	if len(commentPaths) > 0 && len(commentPaths) != ctx.NArg() {
+		return fmt.Errorf("mismatched number of issue comment snapshots: %d issue comment snapshots for %d issue snapshots", len(commentPaths), ctx.NArg())
+	}
+	reviewPaths := ctx.StringSlice("reviews")
+	if len(reviewPaths) > 0 && len(reviewPaths) != ctx.NArg() {
+		return fmt.Errorf("mismatched number of review snapshots: %d review snapshots for %d issue snapshots", len(reviewPaths), ctx.NArg())
+	}
+	location, err := time.LoadLocation(ctx.String("tz"))
+	if err != nil {
+		return err
+	}
+	var identities *identity.Map
+	if path := ctx.String("identities"); path != "" {
+		identities, err = readIdentities(path)
+		if err != nil {
+			return err
+		}
+	}
+	opts.Location = func(account github.Account) *time.Location {
+		if location, ok := identities.Location(account.Login); ok {
+			return location
+		}
+		return location
+	}
+	if path := ctx.String("maintainers"); path != "" {
+		maintainers, err := readMaintainers(path)
+		if err != nil {
+			return err
+		}
+		opts.Maintainers = set.New(maintainers...)
+	}
+	opts.WorkingHours, err = parseWorkingHours(ctx.String("working-hours"))
+	if err != nil {
+		return err
+	}
+	opts.Bots, err = parseBotClassifier(ctx)
+	if err != nil {
+		return err
+	}
+	format := strings.ToLower(ctx.String("format"))
+	switch format {
+	case "markdown", "terminal":
+	default:
+		return fmt.Errorf("invalid format: %s", format)
+	}
+
+	// generate report
+	fmt.Println("Activity Report")
+	fmt.Println("===============")
+	printTimeFrame(opts.TimeFrame)
+	fmt.Println("- Time zone:", location)
+	if identities != nil {
+		fmt.Println("- Time zones of people from identities")
+	}
+	fmt.Printf("- Working hours: %02d:00-%02d:00 on weekdays\n", opts.WorkingHours.Start, opts.WorkingHours.End)
+	overall := analysis.NewActivitySummary()
+	overall.TimeFrame = opts.TimeFrame
+	for i, path := range ctx.Args().Slice() {
+		snapshotOpts := opts
+		snapshotOpts.Issues, err = readIssues(path)
+		if err != nil {
+			return err
+		}
+		if len(commentPaths) > 0 {
+			snapshotOpts.Comments, err = readIssueComments(commentPaths[i])
+			if err != nil {
+				return err
+			}
+		}
+		if len(reviewPaths) > 0 {
+			snapshotOpts.Reviews, err = readPullRequestReviews(reviewPaths[i])
+			if err != nil {
+				return err
+			}
+		}
+		summary := analysis.SummarizeActivity(snapshotOpts)
+		overall.Union(summary)
+		fmt.Println()
+		fmt.Println("##", path)
+		printActivitySummary(summary, format)
+	}
+	if ctx.NArg() > 1 {
+		fmt.Println()
+		fmt.Println("## Overall")
+		printActivitySummary(overall, format)
+	}
+	return nil
+}
+
+func parseWorkingHours(s string) (analysis.WorkingHours, error) {
+	start, end, ok := strings.Cut(s, "-")
+	if !ok {
+		return analysis.WorkingHours{}, fmt.Errorf("invalid working hours: %s", s)
+	}
+	var hours analysis.WorkingHours
+	var err error
+	if hours.Start, err = strconv.Atoi(start); err != nil {
+		return analysis.WorkingHours{}, fmt.Errorf("invalid working hours: %s", s)
+	}
+	if hours.End, err = strconv.Atoi(end); err != nil {
+		return analysis.WorkingHours{}, fmt.Errorf("invalid working hours: %s", s)
+	}
+	if hours.Start < 0 || hours.End > 24 || hours.Start >= hours.End {
+		return analysis.WorkingHours{}, fmt.Errorf("invalid working hours: %s", s)
+	}
+	return hours, nil
+}
+
+func printActivitySummary(summary *analysis.ActivitySummary, format string) {
+	var all analysis.Heatmap
+	heatmaps := []struct {
+		name    string
+		heatmap *analysis.Heatmap
+	}{
+		{"All Activities", &all},
+		{"Issue and Pull Request Creation", &summary.Created},
+		{"Comments", &summary.Comments},
+		{"Reviews", &summary.Reviews},
+		{"Merges", &summary.Merges},
+	}
+	for _, h := range heatmaps[1:] {
+		all.Union(h.heatmap)
+	}
+	for _, h := range heatmaps {
+		if h.heatmap.Total() == 0 {
+			continue
+		}
+		fmt.Println()
+		fmt.Printf("### %s: %d\n", h.name, h.heatmap.Total())
+		fmt.Println()
+		if format == "terminal" {
+			printTerminalHeatmap(h.heatmap)
+		} else {
+			printMarkdownHeatmap(h.heatmap)
+		}
+	}
+
+	if len(summary.Responders) > 0 {
+		fmt.Println()
+		fmt.Println("### First Responses Outside Working Hours")
+		fmt.Println()
+		total := 0
+		for _, count := range summary.Responders {
+			total += count
+		}
+		fmt.Printf("- Off-hours share: %.1f%% of %d first responses\n", 100*summary.OffHoursShare(), total)
+		fmt.Println()
+		responders := maps.Keys(summary.Responders)
+		slices.SortFunc(responders, func(a, b string) int {
+			if c := cmp.Compare(summary.Responders[b], summary.Responders[a]); c != 0 {
+				return c
+			}
+			return cmp.Compare(a, b)
+		})
+		table := markdown.NewTable("Responder", "First Responses", "Off Hours", "Share")
+		for _, responder := range responders {
+			count, offHours := summary.Responders[responder], summary.OffHours[responder]
+			table.AddRow("@"+responder, count, offHours, fmt.Sprintf("%.1f%%", 100*float64(offHours)/float64(count)))
+		}
+		table.Print(os.Stdout)
+	}
+}
+
+// heatLevel returns the index of count in heatLevels relative to max.
+func heatLevel(count, max int) int {
+	return min(count*len(heatLevels)/max, len(heatLevels)-1)
+}
+
+func printMarkdownHeatmap(heatmap *analysis.Heatmap) {
+	header := []string{"Day"}
+	for hour := 0; hour < 24; hour++ {
+		header = append(header, fmt.Sprintf("%02d", hour))
+	}
+	header = append(header, "Total")
+	max := heatmap.Max()
+	table := markdown.NewTable(header...)
+	for day, counts := range heatmap {
+		row := []any{weekdays[day]}
+		total := 0
+		for _, count := range counts {
+			if count == 0 {
+				row = append(row, "")
+			} else {
+				row = append(row, heatLevels[heatLevel(count, max)])
+			}
+			total += count
+		}
+		table.AddRow(append(row, total)...)
+	}
+	table.Print(os.Stdout)
+	fmt.Println()
+	fmt.Printf("Legend: %s from 1 to %d per hour\n", strings.Join(heatLevels, ""), max)
+}
+
+func printTerminalHeatmap(heatmap *analysis.Heatmap) {
+	fmt.Print("    ")
+	for hour := 0; hour < 24; hour += 3 {
+		fmt.Printf("%-6s", fmt.Sprintf("%02d", hour))
+	}
+	fmt.Println()
+	max := heatmap.Max()
+	for day, counts := range heatmap {
+		fmt.Print(weekdays[day], " ")
+		for _, count := range counts {
+			color := heatColors[0]
+			if count > 0 {
+				color = heatColors[1+heatLevel(count, max)]
+			}
+			fmt.Printf("\x1b[48;5;%dm  \x1b[0m", color)
+		}
+		fmt.Println()
+	}
+	fmt.Println()
+	fmt.Print("Legend: ")
+	for _, color := range heatColors {
+		fmt.Printf("\x1b[48;5;%dm  \x1b[0m", color)
+	}
+	fmt.Printf(" from 0 to %d per hour\n", max)
+}
@@ -0,0 +1,367 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shizhMSFT/gha/pkg/analysis"
+	"github.com/shizhMSFT/gha/pkg/github"
+	"github.com/shizhMSFT/gha/pkg/markdown"
+	"github.com/urfave/cli/v3"
+	"golang.org/x/exp/maps"
+)
+
+var auditCommand = &cli.Command{
+	Name:      "audit",
+	Usage:     "audit merged pull requests for approvals by someone other than the author",
+	ArgsUsage: "<issue_snapshot> [...]",
+	Aliases:   []string{"au"},
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:     "ago",
+			Usage:    "only include pull requests merged at least `DAYS` ago",
+			OnlyOnce: true,
+		},
+		&cli.TimestampFlag{
+			Name:     "start-date",
+			Usage:    "only include pull requests that were merged after `DATE`",
+			Config:   cli.TimestampConfig{Layout: time.DateOnly},
+			OnlyOnce: true,
+		},
+		&cli.TimestampFlag{
+			Name:     "end-date",
+			Usage:    "only include pull requests that were merged before `DATE`",
+			Config:   cli.TimestampConfig{Layout: time.DateOnly},
+			OnlyOnce: true,
+		},
+		&cli.StringSliceFlag{
+			Name:     "reviews",
+			Usage:    "specify review `SNAPSHOT` files in the same order as the issue snapshots",
+			Required: true,
+		},
+		&cli.StringSliceFlag{
+			Name:  "timelines",
+			Usage: "specify timeline `SNAPSHOT` files in the same order as the issue snapshots to resolve mergers and dismissed approvals",
+		},
+		&cli.StringFlag{
+			Name:     "identities",
+			Usage:    "resolve accounts to people with an identity `FILE`",
+			OnlyOnce: true,
+		},
+		&cli.StringFlag{
+			Name:     "format",
+			Usage:    "output format in `{markdown, json}`",
+			Value:    "markdown",
+			OnlyOnce: true,
+		},
+		botPatternFlag(),
+	},
+	Action: runAudit,
+}
+
+func runAudit(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return errors.New("no issue snapshot files specified")
+	}
+
+	// parse flags
+	var opts analysis.SummarizeAuditOptions
+	if ago := ctx.Int("ago"); ago > 0 {
+		opts.TimeFrame.Start = time.Now().UTC().AddDate(0, 0, int(-ago))
+	}
+	if date := ctx.Value("start-date").(time.Time); !date.IsZero() {
+		opts.TimeFrame.Start = date
+	}
+	if date := ctx.Value("end-date").(time.Time); !date.IsZero() {
+		opts.TimeFrame.End = date
+	}
+	reviewPaths := ctx.StringSlice("reviews")
+	if len(reviewPaths) != ctx.NArg() {
+		return fmt.Errorf("mismatched number of review snapshots: %d review snapshots for %d issue snapshots", len(reviewPaths), ctx.NArg())
+	}
+	timelinePaths := ctx.StringSlice("timelines")
+	if len(timelinePaths) > 0 && len(timelinePaths) != ctx.NArg() {
+		return fmt.Errorf("mismatched number of timeline snapshots: %d timeline snapshots for %d issue snapshots", len(timelinePaths), ctx.NArg())
+	}
+	format := strings.ToLower(ctx.String("format"))
+	switch format {
+	case "markdown", "json":
+	default:
+		return fmt.Errorf("invalid format: %s", format)
+	}
+	var err error
+	if path := ctx.String("identities"); path != "" {
+		opts.Identities, err = readIdentities(path)
+		if err != nil {
+			return err
+		}
+	}
+	opts.Bots, err = parseBotClassifier(ctx)
+	if err != nil {
+		return err
+	}
+
+	// audit snapshots
+	summaries := make([]*analysis.AuditSummary, 0, ctx.NArg())
+	snapshots := make([]map[int]github.Issue, 0, ctx.NArg())
+	for i, path := range ctx.Args().Slice() {
+		snapshotOpts := opts
+		snapshotOpts.Issues, err = readIssues(path)
+		if err != nil {
+			return err
+		}
+		snapshotOpts.Reviews, err = readPullRequestReviews(reviewPaths[i])
+		if err != nil {
+			return err
+		}
+		if len(timelinePaths) > 0 {
+			snapshotOpts.Timelines, err = readTimelines(timelinePaths[i])
+			if err != nil {
+				return err
+			}
+		}
+		summaries = append(summaries, analysis.SummarizeAudit(snapshotOpts))
+		snapshots = append(snapshots, snapshotOpts.Issues)
+	}
+	if format == "json" {
+		return writeAuditJSON(ctx.Args().Slice(), summaries, snapshots)
+	}
+
+	// generate report
+	fmt.Println("Merge Audit")
+	fmt.Println("===========")
+	printTimeFrame(opts.TimeFrame)
+	for i, path := range ctx.Args().Slice() {
+		fmt.Println()
+		fmt.Println("##", path)
+		printAuditSummary(summaries[i])
+		printAuditFindings(summaries[i], snapshots[i])
+	}
+	if ctx.NArg() > 1 {
+		fmt.Println()
+		fmt.Println("## Overall")
+		printAuditSummary(summaries...)
+	}
+	return nil
+}
+
+func printAuditSummary(summaries ...*analysis.AuditSummary) {
+	var merged, violations, missing, selfMerges, dismissed, unknown int
+	for _, summary := range summaries {
+		merged += len(summary.Audits)
+		violations += summary.Violations.Len()
+		missing += summary.MissingReviews.Len()
+		selfMerges += summary.SelfMerges.Len()
+		dismissed += summary.Dismissed.Len()
+		unknown += summary.UnknownMergers.Len()
+	}
+	fmt.Println()
+	fmt.Println("- Merged pull requests:", merged)
+	approved := merged - violations - missing
+	fmt.Printf("  - Approved by others: %d (%s)\n", approved, formatShare(approved, merged))
+	fmt.Printf("  - Violations: %d (%s)\n", violations, formatShare(violations, merged))
+	if missing > 0 {
+		fmt.Printf("  - Missing review data: %d (%s)\n", missing, formatShare(missing, merged))
+	}
+	switch known := merged - unknown; {
+	case known == 0 && merged > 0:
+		fmt.Println("  - Self-merges: N/A (mergers unknown, specify --timelines)")
+	case unknown > 0:
+		fmt.Printf("  - Self-merges: %d of %d with known mergers (%s)\n", selfMerges, known, formatShare(selfMerges, known))
+	default:
+		fmt.Printf("  - Self-merges: %d (%s)\n", selfMerges, formatShare(selfMerges, merged))
+	}
+	fmt.Println("  - Approvals dismissed after merge:", dismissed)
+	if unknown > 0 {
+		fmt.Println("  - Unknown mergers:", unknown)
+	}
+}
+
+// printAuditFindings lists the violations, the pull requests without review
+// data, the self-merges and the approvals dismissed after merge.
+func printAuditFindings(summary *analysis.AuditSummary, issues map[int]github.Issue) {
+	if numbers := sortedNumbers(maps.Keys(summary.Violations)); len(numbers) > 0 {
+		fmt.Println()
+		fmt.Println("### Merged without Approval")
+		fmt.Println()
+		table := markdown.NewTable("#PR", "Author", "Merged By", "Merged At", "Dismissed Reviews", "Title")
+		for _, number := range numbers {
+			audit := summary.Audits[number]
+			table.AddRow(
+				fmt.Sprintf("#%d", number),
+				"@"+audit.Author,
+				formatMerger(audit.MergedBy),
+				audit.MergedAt.Format(time.DateTime),
+				audit.DismissedReviews,
+				issues[number].Title,
+			)
+		}
+		table.Print(os.Stdout)
+	}
+	if numbers := sortedNumbers(maps.Keys(summary.MissingReviews)); len(numbers) > 0 {
+		fmt.Println()
+		fmt.Println("### Missing Review Data")
+		fmt.Println()
+		table := markdown.NewTable("#PR", "Author", "Merged By", "Merged At", "Title")
+		for _, number := range numbers {
+			audit := summary.Audits[number]
+			table.AddRow(
+				fmt.Sprintf("#%d", number),
+				"@"+audit.Author,
+				formatMerger(audit.MergedBy),
+				audit.MergedAt.Format(time.DateTime),
+				issues[number].Title,
+			)
+		}
+		table.Print(os.Stdout)
+	}
+	if numbers := sortedNumbers(maps.Keys(summary.SelfMerges)); len(numbers) > 0 {
+		fmt.Println()
+		fmt.Println("### Self-merges")
+		fmt.Println()
+		table := markdown.NewTable("#PR", "Author", "Approvers", "Merged At", "Title")
+		for _, number := range numbers {
+			audit := summary.Audits[number]
+			approvers := "-"
+			if len(audit.Approvers) > 0 {
+				approvers = "@" + strings.Join(audit.Approvers, ", @")
+			}
+			table.AddRow(
+				fmt.Sprintf("#%d", number),
+				"@"+audit.Author,
+				approvers,
+				audit.MergedAt.Format(time.DateTime),
+				issues[number].Title,
+			)
+		}
+		table.Print(os.Stdout)
+	}
+	if numbers := sortedNumbers(maps.Keys(summary.Dismissed)); len(numbers) > 0 {
+		fmt.Println()
+		fmt.Println("### Approvals Dismissed after Merge")
+		fmt.Println()
+		table := markdown.NewTable("#PR", "Reviewer", "Approved At", "Dismissed By", "Dismissed At", "Title")
+		for _, number := range numbers {
+			for _, dismissal := range summary.Audits[number].DismissedApprovals {
+				table.AddRow(
+					fmt.Sprintf("#%d", number),
+					"@"+dismissal.Reviewer,
+					dismissal.ApprovedAt.Format(time.DateTime),
+					"@"+dismissal.DismissedBy,
+					dismissal.DismissedAt.Format(time.DateTime),
+					issues[number].Title,
+				)
+			}
+		}
+		table.Print(os.Stdout)
+	}
+}
+
+func formatMerger(login string) string {
+	if login == "" {
+		return "unknown"
+	}
+	return "@" + login
+}
+
+func sortedNumbers(numbers []int) []int {
+	slices.Sort(numbers)
+	return numbers
+}
+
+type auditJSON struct {
+	Snapshot       string           `json:"snapshot"`
+	Start          *time.Time       `json:"start,omitempty"`
+	End            *time.Time       `json:"end,omitempty"`
+	Merged         int              `json:"merged"`
+	Violations     int              `json:"violations"`
+	MissingReviews int              `json:"missing_reviews"`
+	SelfMerges     *int             `json:"self_merges"` // null if all mergers are unknown
+	Dismissed      int              `json:"dismissed_after_merge"`
+	UnknownMergers int              `json:"unknown_mergers"`
+	PullRequests   []mergeAuditJSON `json:"pull_requests"`
+}
+
+type mergeAuditJSON struct {
+	Number             int                     `json:"number"`
+	Title              string                  `json:"title"`
+	HTMLURL            string                  `json:"html_url"`
+	Author             string                  `json:"author"`
+	MergedBy           string                  `json:"merged_by,omitempty"`
+	MergedAt           time.Time               `json:"merged_at"`
+	Approvers          []string                `json:"approvers"`
+	DismissedApprovals []dismissedApprovalJSON `json:"dismissed_approvals,omitempty"`
+	DismissedReviews   int                     `json:"dismissed_reviews,omitempty"`
+	Violation          bool                    `json:"violation"`
+	MissingReviews     bool                    `json:"missing_reviews,omitempty"`
+	SelfMerged         *bool                   `json:"self_merged"` // null if the merger is unknown
+}
+
+type dismissedApprovalJSON struct {
+	Reviewer    string    `json:"reviewer"`
+	ApprovedAt  time.Time `json:"approved_at"`
+	DismissedBy string    `json:"dismissed_by"`
+	DismissedAt time.Time `json:"dismissed_at"`
+}
+
+// writeAuditJSON writes the audit records of all merged pull requests per
+// snapshot as JSON.
+func writeAuditJSON(paths []string, summaries []*analysis.AuditSummary, snapshots []map[int]github.Issue) error {
+	reports := make([]auditJSON, 0, len(summaries))
+	for i, summary := range summaries {
+		report := auditJSON{
+			Snapshot:       paths[i],
+			Merged:         len(summary.Audits),
+			Violations:     summary.Violations.Len(),
+			MissingReviews: summary.MissingReviews.Len(),
+			Dismissed:      summary.Dismissed.Len(),
+			UnknownMergers: summary.UnknownMergers.Len(),
+			PullRequests:   make([]mergeAuditJSON, 0, len(summary.Audits)),
+		}
+		if report.UnknownMergers < report.Merged || report.Merged == 0 {
+			selfMerges := summary.SelfMerges.Len()
+			report.SelfMerges = &selfMerges
+		}
+		if !summary.Start.IsZero() {
+			report.Start = &summary.Start
+		}
+		if !summary.End.IsZero() {
+			report.End = &summary.End
+		}
+		for _, number := range sortedNumbers(maps.Keys(summary.Audits)) {
+			audit := summary.Audits[number]
+			record := mergeAuditJSON{
+				Number:           number,
+				Title:            snapshots[i][number].Title,
+				HTMLURL:          snapshots[i][number].HTMLURL,
+				Author:           audit.Author,
+				MergedBy:         audit.MergedBy,
+				MergedAt:         audit.MergedAt,
+				Approvers:        audit.Approvers,
+				DismissedReviews: audit.DismissedReviews,
+				Violation:        summary.Violations.Contains(number),
+				MissingReviews:   summary.MissingReviews.Contains(number),
+			}
+			if audit.MergedBy != "" {
+				selfMerged := summary.SelfMerges.Contains(number)
+				record.SelfMerged = &selfMerged
+			}
+			if record.Approvers == nil {
+				record.Approvers = []string{}
+			}
+			for _, dismissal := range audit.DismissedApprovals {
+				record.DismissedApprovals = append(record.DismissedApprovals, dismissedApprovalJSON(dismissal))
+			}
+			report.PullRequests = append(report.PullRequests, record)
+		}
+		reports = append(reports, report)
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(reports)
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shizhMSFT/gha/pkg/analysis"
+	"github.com/shizhMSFT/gha/pkg/github"
+	"github.com/urfave/cli/v3"
+)
+
+var backlogCommand = &cli.Command{
+	Name:      "backlog",
+	Usage:     "analyze the backlog and cumulative flow over time",
+	ArgsUsage: "<snapshot> [...]",
+	Aliases:   []string{"b"},
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:     "ago",
+			Usage:    "only include days that are at least `DAYS` old",
+			OnlyOnce: true,
+		},
+		&cli.TimestampFlag{
+			Name:     "start-date",
+			Usage:    "only include days after `DATE`",
+			Config:   cli.TimestampConfig{Layout: time.DateOnly},
+			OnlyOnce: true,
+		},
+		&cli.TimestampFlag{
+			Name:     "end-date",
+			Usage:    "only include days before `DATE`",
+			Config:   cli.TimestampConfig{Layout: time.DateOnly},
+			OnlyOnce: true,
+		},
+		&cli.StringFlag{
+			Name:     "bucket",
+			Usage:    "summarize the flow in time buckets of `{week, month, quarter}`",
+			Value:    "month",
+			OnlyOnce: true,
+		},
+		&cli.StringFlag{
+			Name:     "format",
+			Usage:    "output format in `{markdown, csv}`, where csv prints the daily series of all snapshots combined",
+			Value:    "markdown",
+			OnlyOnce: true,
+		},
+	},
+	Action: runBacklog,
+}
+
+func runBacklog(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return errors.New("no snapshot files specified")
+	}
+
+	// parse flags
+	var timeFrame analysis.TimeFrame
+	if ago := ctx.Int("ago"); ago > 0 {
+		timeFrame.Start = time.Now().UTC().AddDate(0, 0, int(-ago))
+	}
+	if date := ctx.Value("start-date").(time.Time); !date.IsZero() {
+		timeFrame.Start = date
+	}
+	if date := ctx.Value("end-date").(time.Time); !date.IsZero() {
+		timeFrame.End = date
+	}
+	format := strings.ToLower(ctx.String("format"))
+	switch format {
+	case "markdown", "csv":
+	default:
+		return fmt.Errorf("invalid format: %s", format)
+	}
+
+	// read snapshots
+	snapshots := make([]map[int]github.Issue, 0, ctx.NArg())
+	for _, path := range ctx.Args().Slice() {
+		snapshot, err := readIssues(path)
+		if err != nil {
+			return err
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	dayFrame := timeFrame
+	dayFrame.Start = dayFrame.Start.Truncate(24 * time.Hour)
+	size, buckets, err := parseBuckets(ctx, dayFrame, firstCreated(snapshots...).Truncate(24*time.Hour))
+	if err != nil {
+		return err
+	}
+
+	// rebuild backlogs over the same days
+	days := analysis.TimeFrame{
+		Start: buckets[0].Start,
+		End:   buckets[len(buckets)-1].End,
+	}
+	backlogs := make([]*analysis.Backlog, 0, len(snapshots))
+	overall := &analysis.Backlog{TimeFrame: timeFrame}
+	for _, snapshot := range snapshots {
+		backlog := analysis.SummarizeBacklog(snapshot, days)
+		backlogs = append(backlogs, backlog)
+		overall.Union(backlog)
+	}
+	if format == "csv" {
+		return writeBacklogCSV(os.Stdout, overall)
+	}
+
+	// generate report
+	fmt.Println("Backlog Report")
+	fmt.Println("==============")
+	printTimeFrame(timeFrame)
+	fmt.Println("- Bucket:", size)
+	for i, path := range ctx.Args().Slice() {
+		fmt.Println()
+		fmt.Println("##", path)
+		printBacklog(size, buckets, backlogs[i])
+	}
+	if ctx.NArg() > 1 {
+		fmt.Println()
+		fmt.Println("## Overall")
+		printBacklog(size, buckets, overall)
+	}
+	return nil
+}
+
+func printBacklog(size analysis.BucketSize, buckets []analysis.TimeFrame, backlog *analysis.Backlog) {
+	fmt.Println()
+	fmt.Println("### Issues")
+	printFlow(size, analysis.SummarizeFlow(backlog.Issues, buckets))
+
+	fmt.Println()
+	fmt.Println("### Pull Requests")
+	printFlow(size, analysis.SummarizeFlow(backlog.PullRequests, buckets))
+}
+
+func printFlow(size analysis.BucketSize, summaries []*analysis.FlowSummary) {
+	if len(summaries) == 0 {
+		return
+	}
+	first, last := summaries[0], summaries[len(summaries)-1]
+	growth := last.EndOpen - first.StartOpen
+	fmt.Println()
+	fmt.Println("- Open at start:", first.StartOpen)
+	fmt.Println("- Open at end:", last.EndOpen)
+	if first.StartOpen > 0 {
+		fmt.Printf("- Growth: %+d (%+.1f%%)\n", growth, 100*float64(growth)/float64(first.StartOpen))
+	} else {
+		fmt.Printf("- Growth: %+d\n", growth)
+	}
+
+	table := newTrendTable("Bucket", "Arrivals", "Departures", "Net", "Open", "Avg Open", "Avg Age", "Cycle Time (Little's Law)")
+	for _, summary := range summaries {
+		averageAge := trendCell{text: "-", missing: true}
+		if summary.EndOpen > 0 {
+			averageAge = trendCell{text: formatDuration(summary.AverageAge), value: float64(summary.AverageAge)}
+		}
+		cycleTime := trendCell{text: "-", missing: true}
+		if summary.HasCycleTime {
+			cycleTime = trendCell{text: formatDuration(summary.CycleTime), value: float64(summary.CycleTime)}
+		}
+		table.AddBucket(size.Label(summary.Start),
+			countCell(summary.Arrivals),
+			countCell(summary.Departures),
+			trendCell{text: fmt.Sprintf("%+d", summary.Arrivals-summary.Departures), value: float64(summary.Arrivals - summary.Departures)},
+			countCell(summary.EndOpen),
+			trendCell{text: fmt.Sprintf("%.1f", summary.AverageOpen), value: summary.AverageOpen},
+			averageAge,
+			cycleTime,
+		)
+	}
+	table.Print()
+}
+
+// writeBacklogCSV writes the daily cumulative flow of issues and pull
+// requests as CSV records.
+func writeBacklogCSV(w io.Writer, backlog *analysis.Backlog) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"date", "type", "arrivals", "departures", "cumulative_arrivals", "cumulative_departures", "open", "average_age_days"}); err != nil {
+		return err
+	}
+	for _, series := range []struct {
+		kind   string
+		points []analysis.BacklogPoint
+	}{
+		{"issue", backlog.Issues},
+		{"pull_request", backlog.PullRequests},
+	} {
+		for _, point := range series.points {
+			if err := writer.Write([]string{
+				point.Date.Format(time.DateOnly),
+				series.kind,
+				strconv.Itoa(point.Arrivals),
+				strconv.Itoa(point.Departures),
+				strconv.Itoa(point.CumulativeArrivals),
+				strconv.Itoa(point.CumulativeDepartures),
+				strconv.Itoa(point.Open),
+				strconv.FormatFloat(point.AverageAge().Hours()/24, 'f', 2, 64),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/shizhMSFT/gha/pkg/actor"
+	"github.com/shizhMSFT/gha/pkg/analysis"
+	"github.com/shizhMSFT/gha/pkg/github"
+	"github.com/shizhMSFT/gha/pkg/markdown"
+	"github.com/urfave/cli/v3"
+	"golang.org/x/exp/maps"
+)
+
+// botFlags returns the flags to filter activities of bots.
+func botFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:     "exclude-bots",
+			Usage:    "exclude activities of bots and report them separately",
+			OnlyOnce: true,
+		},
+		&cli.BoolFlag{
+			Name:     "only-bots",
+			Usage:    "only include issues and pull requests authored by bots, with activities of anyone on them",
+			OnlyOnce: true,
+		},
+		botPatternFlag(),
+	}
+}
+
+// botPatternFlag returns the flag to recognize more accounts as bots.
+func botPatternFlag() cli.Flag {
+	return &cli.StringSliceFlag{
+		Name:  "bot-pattern",
+		Usage: "recognize accounts with logins matching the regular expression `PATTERN` as bots",
+	}
+}
+
+func parseBotClassifier(ctx *cli.Context) (*actor.Classifier, error) {
+	return actor.NewClassifier(ctx.StringSlice("bot-pattern")...)
+}
+
+// botFilter filters activities by whether the actors are bots.
+type botFilter struct {
+	classifier *actor.Classifier
+	exclude    bool
+	only       bool
+}
+
+func parseBotFilter(ctx *cli.Context) (*botFilter, error) {
+	filter := &botFilter{
+		exclude: ctx.Bool("exclude-bots"),
+		only:    ctx.Bool("only-bots"),
+	}
+	if filter.exclude && filter.only {
+		return nil, errors.New("--exclude-bots and --only-bots are mutually exclusive")
+	}
+	var err error
+	filter.classifier, err = parseBotClassifier(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+// keep reports whether the items authored by the account are kept.
+func (f *botFilter) keep(account github.Account) bool {
+	switch {
+	case f.exclude:
+		return !f.classifier.IsBot(account)
+	case f.only:
+		return f.classifier.IsBot(account)
+	default:
+		return true
+	}
+}
+
+// issues filters issues and pull requests by their authors.
+func (f *botFilter) issues(issues map[int]github.Issue) map[int]github.Issue {
+	filtered := make(map[int]github.Issue)
+	for number, issue := range issues {
+		if f.keep(issue.User) {
+			filtered[number] = issue
+		}
+	}
+	return filtered
+}
+
+// filterActivities filters the activities, such as reviews and comments, on
+// the issues and pull requests filtered by f.
+// Activities on items not in issues are dropped unless issues is nil, and
+// activities by bots are further dropped if bots are excluded. With only bots
+// included, all the activities on the items authored by bots are kept, so
+// issues must not be nil.
+func filterActivities[T any](f *botFilter, activities map[int][]T, issues map[int]github.Issue, actor func(T) github.Account) map[int][]T {
+	filtered := make(map[int][]T)
+	for number, items := range activities {
+		if _, ok := issues[number]; !ok && issues != nil {
+			continue
+		}
+		if !f.exclude {
+			filtered[number] = items
+			continue
+		}
+		kept := make([]T, 0, len(items))
+		for _, item := range items {
+			if !f.classifier.IsBot(actor(item)) {
+				kept = append(kept, item)
+			}
+		}
+		filtered[number] = kept
+	}
+	return filtered
+}
+
+func reviewer(review github.PullRequestReview) github.Account {
+	return review.User
+}
+
+func commenter(comment github.IssueComment) github.Account {
+	return comment.User
+}
+
+func reviewCommenter(comment github.PullRequestReviewComment) github.Account {
+	return comment.User
+}
+
+// botActivity counts the activities of bots.
+type botActivity struct {
+	classifier *actor.Classifier
+	timeFrame  analysis.TimeFrame
+	issues     map[string]int
+	prs        map[string]int
+	merged     map[string]int
+	reviews    map[string]int
+	comments   map[string]int
+}
+
+// newBotActivity counts the activities of bots in the time frame.
+func newBotActivity(classifier *actor.Classifier, timeFrame analysis.TimeFrame) *botActivity {
+	return &botActivity{
+		classifier: classifier,
+		timeFrame:  timeFrame,
+		issues:     make(map[string]int),
+		prs:        make(map[string]int),
+		merged:     make(map[string]int),
+		reviews:    make(map[string]int),
+		comments:   make(map[string]int),
+	}
+}
+
+func (a *botActivity) addIssues(issues map[int]github.Issue) {
+	for _, issue := range issues {
+		if !a.timeFrame.Contains(issue.CreatedAt) || !a.classifier.IsBot(issue.User) {
+			continue
+		}
+		login := issue.User.Login
+		if issue.IsPullRequest() {
+			a.prs[login]++
+			if issue.Merged() {
+				a.merged[login]++
+			}
+		} else {
+			a.issues[login]++
+		}
+	}
+}
+
+func (a *botActivity) addReviews(reviews map[int][]github.PullRequestReview) {
+	for _, prReviews := range reviews {
+		for _, review := range prReviews {
+			if a.timeFrame.Contains(review.SubmittedAt) && a.classifier.IsBot(review.User) {
+				a.reviews[review.User.Login]++
+			}
+		}
+	}
+}
+
+func (a *botActivity) addComments(comments map[int][]github.IssueComment) {
+	for _, issueComments := range comments {
+		for _, comment := range issueComments {
+			if a.timeFrame.Contains(comment.CreatedAt) && a.classifier.IsBot(comment.User) {
+				a.comments[comment.User.Login]++
+			}
+		}
+	}
+}
+
+// print prints the bot activities in a table with the columns of non-empty
+// activities.
+func (a *botActivity) print() {
+	fmt.Println()
+	fmt.Println("## Bot Activity")
+	fmt.Println()
+	columns := []struct {
+		name   string
+		counts map[string]int
+	}{
+		{"Issues", a.issues},
+		{"PRs", a.prs},
+		{"Merged PRs", a.merged},
+		{"Reviews", a.reviews},
+		{"Comments", a.comments},
+	}
+	totals := make(map[string]int)
+	header := []string{"Bot"}
+	for _, column := range columns {
+		if len(column.counts) == 0 {
+			continue
+		}
+		header = append(header, column.name)
+		for bot, count := range column.counts {
+			totals[bot] += count
+		}
+	}
+	if len(totals) == 0 {
+		fmt.Println("No bot activity")
+		return
+	}
+	bots := maps.Keys(totals)
+	slices.SortFunc(bots, func(a, b string) int {
+		if c := cmp.Compare(totals[b], totals[a]); c != 0 {
+			return c
+		}
+		return cmp.Compare(a, b)
+	})
+	table := markdown.NewTable(header...)
+	for _, bot := range bots {
+		row := []any{"@" + bot}
+		for _, column := range columns {
+			if len(column.counts) > 0 {
+				row = append(row, column.counts[bot])
+			}
+		}
+		table.AddRow(row...)
+	}
+	table.Print(os.Stdout)
+}
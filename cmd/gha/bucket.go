@@ -0,0 +1,125 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/shizhMSFT/gha/pkg/analysis"
+	"github.com/shizhMSFT/gha/pkg/calendar"
+	"github.com/shizhMSFT/gha/pkg/github"
+	"github.com/shizhMSFT/gha/pkg/markdown"
+	"github.com/shizhMSFT/gha/pkg/math"
+	"github.com/urfave/cli/v3"
+)
+
+// parseBuckets splits the time frame into buckets of the size specified by
+// the bucket flag.
+// The open start of the time frame is bounded by first, and the open end by
+// now.
+func parseBuckets(ctx *cli.Context, timeFrame analysis.TimeFrame, first time.Time) (analysis.BucketSize, []analysis.TimeFrame, error) {
+	size, err := analysis.ParseBucketSize(ctx.String("bucket"))
+	if err != nil {
+		return "", nil, err
+	}
+	buckets := timeFrame.Buckets(size, first, time.Now().UTC())
+	if len(buckets) == 0 {
+		return "", nil, errors.New("no buckets in the time frame")
+	}
+	return size, buckets, nil
+}
+
+// firstCreated returns the earliest creation time of the issues.
+func firstCreated(snapshots ...map[int]github.Issue) time.Time {
+	var first time.Time
+	for _, snapshot := range snapshots {
+		for _, issue := range snapshot {
+			if first.IsZero() || issue.CreatedAt.Before(first) {
+				first = issue.CreatedAt
+			}
+		}
+	}
+	return first
+}
+
+// formatTrend formats the value with an arrow comparing it to the previous
+// value.
+func formatTrend(value string, curr, prev float64, hasPrev bool) string {
+	if !hasPrev {
+		return value
+	}
+	switch {
+	case curr > prev:
+		return value + " ↑"
+	case curr < prev:
+		return value + " ↓"
+	default:
+		return value + " →"
+	}
+}
+
+// trendTable builds a markdown table of buckets, where each cell shows the
+// value with the trend against the previous bucket.
+type trendTable struct {
+	*markdown.Table
+	prev map[int]float64
+}
+
+func newTrendTable(header ...string) *trendTable {
+	return &trendTable{
+		Table: markdown.NewTable(header...),
+	}
+}
+
+// trendCell is a cell of a trend table with the formatted value and the value
+// for comparison, where missing values are not compared.
+type trendCell struct {
+	text    string
+	value   float64
+	missing bool
+}
+
+func countCell(count int) trendCell {
+	return trendCell{text: fmt.Sprint(count), value: float64(count)}
+}
+
+func medianCell(durations []time.Duration, cal *calendar.Calendar) trendCell {
+	if len(durations) == 0 {
+		return trendCell{text: "-", missing: true}
+	}
+	slices.Sort(durations)
+	median := math.Median(durations)
+	return trendCell{text: formatWorkingDuration(median, cal), value: float64(median)}
+}
+
+func percentileCell(durations []time.Duration, p float64, cal *calendar.Calendar) trendCell {
+	if len(durations) == 0 {
+		return trendCell{text: "-", missing: true}
+	}
+	slices.Sort(durations)
+	percentile := math.Percentile(durations, p)
+	return trendCell{text: formatWorkingDuration(percentile, cal), value: float64(percentile)}
+}
+
+func (t *trendTable) AddBucket(label string, cells ...trendCell) {
+	row := []any{label}
+	next := make(map[int]float64)
+	for i, cell := range cells {
+		if cell.missing {
+			row = append(row, cell.text)
+			continue
+		}
+		prev, hasPrev := t.prev[i]
+		row = append(row, formatTrend(cell.text, cell.value, prev, hasPrev))
+		next[i] = cell.value
+	}
+	t.prev = next
+	t.AddRow(row...)
+}
+
+func (t *trendTable) Print() {
+	fmt.Println()
+	t.Table.Print(os.Stdout)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/shizhMSFT/gha/pkg/calendar"
+	"github.com/urfave/cli/v3"
+)
+
+// calendarFlag returns the flag to measure durations in working time.
+func calendarFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:     "calendar",
+		Usage:    "measure durations and SLAs in working days of a calendar `FILE` in YAML, or an iCalendar file of holidays",
+		OnlyOnce: true,
+	}
+}
+
+// parseCalendar reads the calendar specified by the calendar flag, or returns
+// nil to measure the wall-clock time.
+func parseCalendar(ctx *cli.Context) (*calendar.Calendar, error) {
+	path := ctx.String("calendar")
+	if path == "" {
+		return nil, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	parse := calendar.Parse
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".ics" || ext == ".ical" {
+		parse = calendar.ParseICal
+	}
+	cal, err := parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cal, nil
+}
+
+// days returns the duration of n days, which are working days of the calendar
+// if not nil.
+func days(n int64, cal *calendar.Calendar) time.Duration {
+	return time.Duration(n) * cal.Day()
+}
+
+// dayUnit returns the unit of the days counted by days.
+func dayUnit(cal *calendar.Calendar) string {
+	if cal != nil {
+		return "working days"
+	}
+	return "days"
+}
+
+// formatWorkingDuration formats the working time of the calendar in working
+// days (wd) and hours, or the wall-clock time if the calendar is nil.
+func formatWorkingDuration(d time.Duration, cal *calendar.Calendar) string {
+	if cal == nil {
+		return formatDuration(d)
+	}
+	if d < 0 {
+		return "-" + formatWorkingDuration(-d, cal)
+	}
+	workingDay := cal.Day()
+	workingDays, rest := d/workingDay, d%workingDay
+	hours, minutes := rest/time.Hour, rest%time.Hour/time.Minute
+	switch {
+	case workingDays > 0 && hours > 0:
+		return fmt.Sprintf("%dwd %dh", workingDays, hours)
+	case workingDays > 0:
+		return fmt.Sprintf("%dwd", workingDays)
+	case hours > 0 && minutes > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
+
+// printCalendar prints the working hours of the calendar if not nil.
+func printCalendar(cal *calendar.Calendar) {
+	if cal == nil {
+		return
+	}
+	var workdays []string
+	for day, working := range cal.Days {
+		if working {
+			workdays = append(workdays, time.Weekday(day).String()[:3])
+		}
+	}
+	fmt.Printf("- Calendar: durations in working days (wd) and hours of %s-%s on %s in %s, excluding %d holidays\n",
+		formatTimeOfDay(cal.Start), formatTimeOfDay(cal.End), strings.Join(workdays, ", "), cal.Location, cal.Holidays.Len())
+}
+
+func formatTimeOfDay(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
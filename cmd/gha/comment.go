@@ -4,26 +4,24 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"regexp"
-	"slices"
 	"time"
 
 	"github.com/shizhMSFT/gha/pkg/analysis"
+	"github.com/shizhMSFT/gha/pkg/calendar"
 	"github.com/shizhMSFT/gha/pkg/container/set"
 	"github.com/shizhMSFT/gha/pkg/github"
 	"github.com/shizhMSFT/gha/pkg/markdown"
-	"github.com/shizhMSFT/gha/pkg/math"
+	"github.com/shizhMSFT/gha/pkg/owners"
+	"github.com/shizhMSFT/gha/pkg/sort"
 	"github.com/urfave/cli/v3"
 )
 
-var maintainerRegexp = regexp.MustCompile(`(?:[^\w]+@|^@)([\w-]+)`)
-
 var issueCommentCommand = &cli.Command{
 	Name:      "issue-comment",
 	Usage:     "analyze issue comments",
 	ArgsUsage: "<issue_snapshot> <issue_comment_snapshot>",
 	Aliases:   []string{"ic", "i"},
-	Flags: []cli.Flag{
+	Flags: append([]cli.Flag{
 		&cli.IntFlag{
 			Name:     "ago",
 			Usage:    "only include snapshots that are at least `DAYS` old",
@@ -46,13 +44,29 @@ var issueCommentCommand = &cli.Command{
 			Usage:    "report issues that have not received a comment from a maintainer more than `DAYS`",
 			OnlyOnce: true,
 		},
+		&cli.StringFlag{
+			Name:     "reviews",
+			Usage:    "analyze first responses to pull requests with a review `SNAPSHOT` file",
+			OnlyOnce: true,
+		},
+		&cli.StringFlag{
+			Name:     "review-comments",
+			Usage:    "analyze first responses to pull requests with a review comment `SNAPSHOT` file",
+			OnlyOnce: true,
+		},
+		calendarFlag(),
 		&cli.StringFlag{
 			Name:     "maintainers",
-			Usage:    "specify a file containing a list of maintainer GitHub accounts",
+			Usage:    "specify a CODEOWNERS, OWNERS or MAINTAINERS file listing maintainer GitHub accounts",
 			Required: true,
 			OnlyOnce: true,
 		},
-	},
+		&cli.StringFlag{
+			Name:     "bucket",
+			Usage:    "report trends in time buckets of `{week, month, quarter}`",
+			OnlyOnce: true,
+		},
+	}, botFlags()...),
 	Action: runIssueComment,
 }
 
@@ -78,7 +92,11 @@ func runIssueComment(ctx *cli.Context) error {
 	}
 	opts.Maintainers = set.New(maintainers...)
 	slaDays := ctx.Int("sla")
-	sla := time.Duration(slaDays) * time.Hour * 24
+	opts.Calendar, err = parseCalendar(ctx)
+	if err != nil {
+		return err
+	}
+	sla := days(slaDays, opts.Calendar)
 
 	// read issue snapshot base
 	opts.Issues, err = readIssues(ctx.Args().First())
@@ -92,46 +110,148 @@ func runIssueComment(ctx *cli.Context) error {
 		return err
 	}
 
+	// read review snapshots
+	if path := ctx.String("reviews"); path != "" {
+		opts.Reviews, err = readPullRequestReviews(path)
+		if err != nil {
+			return err
+		}
+	}
+	if path := ctx.String("review-comments"); path != "" {
+		opts.ReviewComments, err = readPullRequestReviewComments(path)
+		if err != nil {
+			return err
+		}
+	}
+	pullRequests := opts.Reviews != nil || opts.ReviewComments != nil
+
+	// filter bots
+	bots, err := parseBotFilter(ctx)
+	if err != nil {
+		return err
+	}
+	activity := newBotActivity(bots.classifier, opts.TimeFrame)
+	activity.addIssues(analysis.Filter(opts.Issues, func(issue github.Issue) bool {
+		return pullRequests || !issue.IsPullRequest()
+	}))
+	activity.addComments(opts.Comments)
+	activity.addReviews(opts.Reviews)
+	opts.Issues = bots.issues(opts.Issues)
+	opts.Comments = filterActivities(bots, opts.Comments, opts.Issues, commenter)
+	opts.Reviews = filterActivities(bots, opts.Reviews, opts.Issues, reviewer)
+	opts.ReviewComments = filterActivities(bots, opts.ReviewComments, opts.Issues, reviewCommenter)
+
+	if ctx.String("bucket") != "" {
+		return runIssueCommentBuckets(ctx, opts, pullRequests)
+	}
+
 	// generate report
 	fmt.Println("Issue Comment Summary")
 	fmt.Println("=====================")
 	printTimeFrame(opts.TimeFrame)
+	printCalendar(opts.Calendar)
 	fmt.Println()
 	fmt.Println("## Maintainers")
 	fmt.Println()
 	for _, maintainer := range maintainers {
 		fmt.Printf("- @%s\n", maintainer)
 	}
+	now := opts.TimeFrame.End
+	if now.IsZero() {
+		now = time.Now()
+	}
+	fmt.Println()
+	fmt.Println("## First Response Time")
 	report := analysis.SummarizeIssueComments(opts)
-	printIssueCommentSummary(report)
+	printIssueCommentSummary(report, "issues", opts.Calendar)
 	if sla > 0 {
 		fmt.Println()
-		fmt.Println("### Out of SLA:", slaDays, "Days")
+		fmt.Println("### Out of SLA:", slaDays, dayUnit(opts.Calendar))
+		printResponseOutOfSLA(report.OutOfSLA(sla, now, opts.Calendar), opts.Issues, "#Issue", "issues", opts.Calendar)
+	}
+	if pullRequests {
+		opts.PullRequests = true
 		fmt.Println()
-		now := opts.TimeFrame.End
-		if now.IsZero() {
-			now = time.Now()
-		}
-		issues := report.OutOfSLA(sla, now)
-		if len(issues) > 0 {
-			table := markdown.NewTable("#Issue", "Duration", "Title")
-			for _, issue := range issues {
-				rawIssue := opts.Issues[issue.Key]
-				table.AddRow(
-					fmt.Sprintf("#%d", issue.Key),
-					formatDuration(issue.Value),
-					rawIssue.Title,
-				)
-			}
-			table.Print(os.Stdout)
-		} else {
-			fmt.Println("No issues out of SLA")
+		fmt.Println("## Pull Request First Response Time")
+		report := analysis.SummarizeIssueComments(opts)
+		printIssueCommentSummary(report, "pull requests", opts.Calendar)
+		if sla > 0 {
+			fmt.Println()
+			fmt.Println("### Pull Requests Out of SLA:", slaDays, dayUnit(opts.Calendar))
+			printResponseOutOfSLA(report.OutOfSLA(sla, now, opts.Calendar), opts.Issues, "#PR", "pull requests", opts.Calendar)
 		}
 	}
+	if bots.exclude {
+		activity.print()
+	}
 
 	return nil
 }
 
+// printResponseOutOfSLA prints the issues or pull requests without a response
+// within the SLA.
+func printResponseOutOfSLA(outOfSLA sort.MapEntrySlice[int, time.Duration], issues map[int]github.Issue, column, kind string, cal *calendar.Calendar) {
+	fmt.Println()
+	if len(outOfSLA) == 0 {
+		fmt.Printf("No %s out of SLA\n", kind)
+		return
+	}
+	table := markdown.NewTable(column, "Duration", "Title")
+	for _, issue := range outOfSLA {
+		table.AddRow(
+			fmt.Sprintf("#%d", issue.Key),
+			formatWorkingDuration(issue.Value, cal),
+			issues[issue.Key].Title,
+		)
+	}
+	table.Print(os.Stdout)
+}
+
+func runIssueCommentBuckets(ctx *cli.Context, opts analysis.SummarizeIssueCommentsOptions, pullRequests bool) error {
+	size, buckets, err := parseBuckets(ctx, opts.TimeFrame, firstCreated(opts.Issues))
+	if err != nil {
+		return err
+	}
+
+	// generate report
+	fmt.Println("Issue Comment Trends")
+	fmt.Println("====================")
+	printTimeFrame(opts.TimeFrame)
+	printCalendar(opts.Calendar)
+	fmt.Println("- Bucket:", size)
+	fmt.Println()
+	fmt.Println("## First Response Time")
+	printIssueCommentBuckets(size, buckets, opts, "Non-maintainer Issues")
+	if pullRequests {
+		opts.PullRequests = true
+		fmt.Println()
+		fmt.Println("## Pull Request First Response Time")
+		printIssueCommentBuckets(size, buckets, opts, "Non-maintainer PRs")
+	}
+	return nil
+}
+
+func printIssueCommentBuckets(size analysis.BucketSize, buckets []analysis.TimeFrame, opts analysis.SummarizeIssueCommentsOptions, column string) {
+	table := newTrendTable("Bucket", column, "Responded", "No Response", "Median", "P90")
+	for _, bucket := range buckets {
+		bucketOpts := opts
+		bucketOpts.TimeFrame = bucket
+		summary := analysis.SummarizeIssueComments(bucketOpts)
+		durations := make([]time.Duration, 0, len(summary.Responded))
+		for _, duration := range summary.Responded {
+			durations = append(durations, duration)
+		}
+		table.AddBucket(size.Label(bucket.Start),
+			countCell(len(summary.Responded)+len(summary.NoResponse)),
+			countCell(len(summary.Responded)),
+			countCell(len(summary.NoResponse)),
+			medianCell(durations, opts.Calendar),
+			percentileCell(durations, 0.9, opts.Calendar),
+		)
+	}
+	table.Print()
+}
+
 func readIssueComments(path string) (map[int][]github.IssueComment, error) {
 	snapshotJSON, err := os.ReadFile(path)
 	if err != nil {
@@ -140,38 +260,44 @@ func readIssueComments(path string) (map[int][]github.IssueComment, error) {
 	return github.ParseIssueComments(snapshotJSON)
 }
 
-func printIssueCommentSummary(summary *analysis.IssueCommentSummary) {
-	fmt.Println()
-	fmt.Println("## First Response Time")
+func readPullRequestReviewComments(path string) (map[int][]github.PullRequestReviewComment, error) {
+	snapshotJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return github.ParsePullRequestReviewComments(snapshotJSON)
+}
+
+// printIssueCommentSummary prints the first response time of the kind of
+// items, such as issues or pull requests.
+func printIssueCommentSummary(summary *analysis.IssueCommentSummary, kind string, cal *calendar.Calendar) {
 	fmt.Println()
-	fmt.Println("- Non-maintainer issues:", len(summary.Responded)+len(summary.NoResponse))
+	fmt.Printf("- Non-maintainer %s: %d\n", kind, len(summary.Responded)+len(summary.NoResponse))
 	fmt.Println("  - Responded:", len(summary.Responded))
 	durations := make([]time.Duration, 0, len(summary.Responded))
 	for _, duration := range summary.Responded {
 		durations = append(durations, duration)
 	}
-	if len(durations) > 0 {
-		slices.Sort(durations)
-		fmt.Println("    - Min:", formatDuration(math.Min(durations)))
-		fmt.Println("    - Max:", formatDuration(math.Max(durations)))
-		fmt.Println("    - Mean:", formatDuration(math.Mean(durations)))
-		fmt.Println("    - Median:", formatDuration(math.Median(durations)))
-		fmt.Println("    - 90th percentile:", formatDuration(math.Percentile(durations, 0.9)))
-		fmt.Println("    - 95th percentile:", formatDuration(math.Percentile(durations, 0.95)))
-		fmt.Println("    - 99th percentile:", formatDuration(math.Percentile(durations, 0.99)))
-	}
+	printDurationStats("    ", durations, cal)
 	fmt.Println("  - No Response:", len(summary.NoResponse))
 }
 
 func readMaintainers(path string) ([]string, error) {
+	file, err := readOwners(path)
+	if err != nil {
+		return nil, err
+	}
+	return file.Maintainers(), nil
+}
+
+func readOwners(path string) (*owners.File, error) {
 	content, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
-	matches := maintainerRegexp.FindAllSubmatch(content, -1)
-	maintainers := make([]string, 0, len(matches))
-	for _, match := range matches {
-		maintainers = append(maintainers, string(match[1]))
+	file, err := owners.Parse(path, content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
 	}
-	return maintainers, nil
+	return file, nil
 }
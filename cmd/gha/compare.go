@@ -0,0 +1,197 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shizhMSFT/gha/pkg/analysis"
+	"github.com/shizhMSFT/gha/pkg/calendar"
+	"github.com/shizhMSFT/gha/pkg/markdown"
+	"github.com/shizhMSFT/gha/pkg/math"
+	"github.com/urfave/cli/v3"
+)
+
+const (
+	bootstrapIterations = 1000
+	bootstrapConfidence = 0.95
+)
+
+// parseBaseline parses the baseline period from the compare-to or the
+// baseline-ago flag.
+func parseBaseline(ctx *cli.Context, timeFrame analysis.TimeFrame) (analysis.TimeFrame, error) {
+	if period := ctx.String("compare-to"); period != "" {
+		start, end, ok := strings.Cut(period, "..")
+		if !ok {
+			return analysis.TimeFrame{}, fmt.Errorf("invalid period: %s: expect START..END", period)
+		}
+		var baseline analysis.TimeFrame
+		var err error
+		if start != "" {
+			if baseline.Start, err = time.Parse(time.DateOnly, start); err != nil {
+				return analysis.TimeFrame{}, fmt.Errorf("invalid period: %s: %w", period, err)
+			}
+		}
+		if end != "" {
+			if baseline.End, err = time.Parse(time.DateOnly, end); err != nil {
+				return analysis.TimeFrame{}, fmt.Errorf("invalid period: %s: %w", period, err)
+			}
+		}
+		return baseline, nil
+	}
+
+	ago := int(ctx.Int("baseline-ago"))
+	if timeFrame.Start.IsZero() {
+		return analysis.TimeFrame{}, fmt.Errorf("baseline-ago requires a start date")
+	}
+	end := timeFrame.End
+	if end.IsZero() {
+		end = time.Now().UTC()
+	}
+	return analysis.TimeFrame{
+		Start: timeFrame.Start.AddDate(0, 0, -ago),
+		End:   end.AddDate(0, 0, -ago),
+	}, nil
+}
+
+func runReportComparison(ctx *cli.Context, timeFrame analysis.TimeFrame, bots *botFilter, cal *calendar.Calendar) error {
+	baseline, err := parseBaseline(ctx, timeFrame)
+	if err != nil {
+		return err
+	}
+
+	// generate report
+	fmt.Println("GitHub Analysis Comparison")
+	fmt.Println("==========================")
+	fmt.Println()
+	if cal != nil {
+		printCalendar(cal)
+		fmt.Println()
+	}
+	fmt.Println("Current period:")
+	printTimeFrame(timeFrame)
+	fmt.Println()
+	fmt.Println("Baseline period:")
+	printTimeFrame(baseline)
+	fmt.Println()
+	fmt.Printf("Changes of durations whose %.0f%% bootstrap confidence interval includes zero are marked as noisy.\n", bootstrapConfidence*100)
+	rng := rand.New(rand.NewSource(1))
+	currentReport := analysis.NewReport(timeFrame)
+	currentReport.Calendar = cal
+	baselineReport := analysis.NewReport(baseline)
+	baselineReport.Calendar = cal
+	for _, path := range ctx.Args().Slice() {
+		fmt.Println()
+		fmt.Println("##", path)
+		snapshot, err := readIssues(path)
+		if err != nil {
+			return err
+		}
+		snapshot = bots.issues(snapshot)
+		printComparison(baselineReport.Summarize(path, snapshot), currentReport.Summarize(path, snapshot), cal, rng)
+	}
+	if ctx.NArg() > 1 {
+		fmt.Println()
+		fmt.Println("## Overall")
+		printComparison(baselineReport.Abstract(), currentReport.Abstract(), cal, rng)
+	}
+	return nil
+}
+
+func printComparison(baseline, current *analysis.Summary, cal *calendar.Calendar, rng *rand.Rand) {
+	fmt.Println()
+	fmt.Println("### Issues")
+	table := newComparisonTable(cal)
+	table.addCount("Total", baseline.Issue.Total, current.Issue.Total)
+	table.addCount("Open", baseline.Issue.Open, current.Issue.Open)
+	table.addCount("Closed", baseline.Issue.Closed, current.Issue.Closed)
+	table.addDurations("Median time to close", baseline.Issue.Durations, current.Issue.Durations, math.Median[time.Duration], rng)
+	table.addDurations("P90 time to close", baseline.Issue.Durations, current.Issue.Durations, percentileFunc(0.9), rng)
+	fmt.Println()
+	table.Print(os.Stdout)
+
+	fmt.Println()
+	fmt.Println("### Pull Requests")
+	table = newComparisonTable(cal)
+	table.addCount("Total", baseline.PullRequest.Total, current.PullRequest.Total)
+	table.addCount("Open", baseline.PullRequest.Open, current.PullRequest.Open)
+	table.addCount("Closed", baseline.PullRequest.Closed, current.PullRequest.Closed)
+	table.addCount("Merged", baseline.PullRequest.Merged, current.PullRequest.Merged)
+	table.addDurations("Median time to merge", baseline.PullRequest.Durations, current.PullRequest.Durations, math.Median[time.Duration], rng)
+	table.addDurations("P90 time to merge", baseline.PullRequest.Durations, current.PullRequest.Durations, percentileFunc(0.9), rng)
+	fmt.Println()
+	table.Print(os.Stdout)
+}
+
+func percentileFunc(p float64) func([]time.Duration) time.Duration {
+	return func(s []time.Duration) time.Duration {
+		return math.Percentile(s, p)
+	}
+}
+
+type comparisonTable struct {
+	*markdown.Table
+	cal *calendar.Calendar // formats durations in working time if not nil
+}
+
+func newComparisonTable(cal *calendar.Calendar) *comparisonTable {
+	return &comparisonTable{
+		Table: markdown.NewTable("Metric", "Baseline", "Current", "Δ", "Δ%", fmt.Sprintf("%.0f%% CI of Δ", bootstrapConfidence*100), "Noisy"),
+		cal:   cal,
+	}
+}
+
+func (t *comparisonTable) addCount(metric string, baseline, current int) {
+	t.AddRow(metric, baseline, current, fmt.Sprintf("%+d", current-baseline), formatPercentChange(float64(baseline), float64(current)), "-", "-")
+}
+
+func (t *comparisonTable) addDurations(metric string, baseline, current []time.Duration, stat func([]time.Duration) time.Duration, rng *rand.Rand) {
+	if len(baseline) == 0 || len(current) == 0 {
+		t.AddRow(metric, formatStat(baseline, stat, t.cal), formatStat(current, stat, t.cal), "-", "-", "-", "-")
+		return
+	}
+	baseline = slices.Clone(baseline)
+	slices.Sort(baseline)
+	current = slices.Clone(current)
+	slices.Sort(current)
+	baselineValue, currentValue := stat(baseline), stat(current)
+	lower, upper := math.BootstrapDifference(baseline, current, stat, bootstrapIterations, bootstrapConfidence, rng)
+	noisy := "no"
+	if lower <= 0 && upper >= 0 {
+		noisy = "yes"
+	}
+	t.AddRow(metric,
+		formatWorkingDuration(baselineValue, t.cal),
+		formatWorkingDuration(currentValue, t.cal),
+		formatDurationChange(currentValue-baselineValue, t.cal),
+		formatPercentChange(float64(baselineValue), float64(currentValue)),
+		fmt.Sprintf("[%s, %s]", formatDurationChange(time.Duration(lower), t.cal), formatDurationChange(time.Duration(upper), t.cal)),
+		noisy,
+	)
+}
+
+func formatStat(durations []time.Duration, stat func([]time.Duration) time.Duration, cal *calendar.Calendar) string {
+	if len(durations) == 0 {
+		return "-"
+	}
+	durations = slices.Clone(durations)
+	slices.Sort(durations)
+	return formatWorkingDuration(stat(durations), cal)
+}
+
+func formatDurationChange(d time.Duration, cal *calendar.Calendar) string {
+	if d < 0 {
+		return "-" + formatWorkingDuration(-d, cal)
+	}
+	return "+" + formatWorkingDuration(d, cal)
+}
+
+func formatPercentChange(baseline, current float64) string {
+	if baseline == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%+.1f%%", 100*(current-baseline)/baseline)
+}
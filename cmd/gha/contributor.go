@@ -0,0 +1,287 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shizhMSFT/gha/pkg/analysis"
+	"github.com/shizhMSFT/gha/pkg/github"
+	"github.com/shizhMSFT/gha/pkg/identity"
+	"github.com/shizhMSFT/gha/pkg/markdown"
+	"github.com/shizhMSFT/gha/pkg/math"
+	"github.com/urfave/cli/v3"
+)
+
+var contributorsCommand = &cli.Command{
+	Name:      "contributors",
+	Usage:     "analyze new and returning contributors and cohort retention",
+	ArgsUsage: "<snapshot> [...]",
+	Aliases:   []string{"c"},
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:     "ago",
+			Usage:    "only report periods that are at least `DAYS` old",
+			OnlyOnce: true,
+		},
+		&cli.TimestampFlag{
+			Name:     "start-date",
+			Usage:    "only report periods after `DATE`",
+			Config:   cli.TimestampConfig{Layout: time.DateOnly},
+			OnlyOnce: true,
+		},
+		&cli.TimestampFlag{
+			Name:     "end-date",
+			Usage:    "only report periods before `DATE`",
+			Config:   cli.TimestampConfig{Layout: time.DateOnly},
+			OnlyOnce: true,
+		},
+		&cli.StringFlag{
+			Name:     "bucket",
+			Usage:    "report periods and cohorts of `{week, month, quarter}`",
+			Value:    "month",
+			OnlyOnce: true,
+		},
+		&cli.IntFlag{
+			Name:     "retention",
+			Usage:    "report cohort retention up to `N` periods later",
+			Value:    12,
+			OnlyOnce: true,
+		},
+		&cli.StringFlag{
+			Name:     "identities",
+			Usage:    "resolve accounts to people with an identity `FILE`",
+			OnlyOnce: true,
+		},
+		&cli.StringFlag{
+			Name:     "table",
+			Usage:    "report the `{funnel, cohort, all}` tables, where csv requires a single table",
+			Value:    "all",
+			OnlyOnce: true,
+		},
+		&cli.StringFlag{
+			Name:     "format",
+			Usage:    "output format in `{markdown, csv}`",
+			Value:    "markdown",
+			OnlyOnce: true,
+		},
+		botPatternFlag(),
+	},
+	Action: runContributors,
+}
+
+func runContributors(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return errors.New("no snapshot files specified")
+	}
+
+	// parse flags
+	var timeFrame analysis.TimeFrame
+	if ago := ctx.Int("ago"); ago > 0 {
+		timeFrame.Start = time.Now().UTC().AddDate(0, 0, int(-ago))
+	}
+	if date := ctx.Value("start-date").(time.Time); !date.IsZero() {
+		timeFrame.Start = date
+	}
+	if date := ctx.Value("end-date").(time.Time); !date.IsZero() {
+		timeFrame.End = date
+	}
+	retention := int(ctx.Int("retention"))
+	if retention < 0 {
+		return errors.New("retention must not be negative")
+	}
+	var identities *identity.Map
+	var err error
+	if path := ctx.String("identities"); path != "" {
+		identities, err = readIdentities(path)
+		if err != nil {
+			return err
+		}
+	}
+	bots, err := parseBotClassifier(ctx)
+	if err != nil {
+		return err
+	}
+	table := strings.ToLower(ctx.String("table"))
+	switch table {
+	case "funnel", "cohort", "all":
+	default:
+		return fmt.Errorf("invalid table: %s", table)
+	}
+	format := strings.ToLower(ctx.String("format"))
+	switch format {
+	case "markdown":
+	case "csv":
+		if table == "all" {
+			return errors.New("csv format requires a single table")
+		}
+	default:
+		return fmt.Errorf("invalid format: %s", format)
+	}
+
+	// read snapshots
+	snapshots := make([]map[int]github.Issue, 0, ctx.NArg())
+	for _, path := range ctx.Args().Slice() {
+		snapshot, err := readIssues(path)
+		if err != nil {
+			return err
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	size, buckets, err := parseBuckets(ctx, timeFrame, firstCreated(snapshots...))
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	summaries := analysis.SummarizeContributors(snapshots, buckets, identities, bots)
+	cohorts := analysis.SummarizeCohorts(snapshots, size, identities, bots, now)
+	cohorts = slices.DeleteFunc(cohorts, func(cohort *analysis.Cohort) bool {
+		return !timeFrame.Contains(cohort.Start) && !timeFrame.Contains(cohort.End)
+	})
+
+	if format == "csv" {
+		if table == "funnel" {
+			return writeContributorCSV(os.Stdout, size, summaries)
+		}
+		return writeCohortCSV(os.Stdout, size, cohorts, retention)
+	}
+
+	// generate report
+	fmt.Println("Contributor Report")
+	fmt.Println("==================")
+	printTimeFrame(timeFrame)
+	fmt.Println("- Bucket:", size)
+	if table != "cohort" {
+		fmt.Println()
+		fmt.Println("## New and Returning Contributors")
+		printContributorFunnel(size, summaries)
+	}
+	if table != "funnel" {
+		fmt.Println()
+		fmt.Println("## Cohort Retention")
+		fmt.Println()
+		fmt.Printf("Share of contributors with the first pull request in the %s still contributing N %ss later.\n", size, size)
+		fmt.Println()
+		printCohorts(size, cohorts, retention)
+	}
+	return nil
+}
+
+func printContributorFunnel(size analysis.BucketSize, summaries []*analysis.ContributorSummary) {
+	table := newTrendTable("Bucket", "Contributors", "New", "Returning", "First PRs", "First PR Merge Rate", "First PR Time to Merge", "Veteran PRs", "Veteran Merge Rate", "Veteran Time to Merge")
+	for _, summary := range summaries {
+		table.AddBucket(size.Label(summary.Start),
+			countCell(summary.Contributors()),
+			countCell(summary.New.Len()),
+			countCell(summary.Returning.Len()),
+			countCell(summary.FirstPullRequests.Total),
+			mergeRateCell(&summary.FirstPullRequests),
+			medianCell(summary.FirstPullRequests.Durations, nil),
+			countCell(summary.VeteranPullRequests.Total),
+			mergeRateCell(&summary.VeteranPullRequests),
+			medianCell(summary.VeteranPullRequests.Durations, nil),
+		)
+	}
+	table.Print()
+}
+
+func mergeRateCell(outcome *analysis.PullRequestOutcome) trendCell {
+	rate, ok := outcome.MergeRate()
+	if !ok {
+		return trendCell{text: "-", missing: true}
+	}
+	return trendCell{text: fmt.Sprintf("%.1f%%", 100*rate), value: rate}
+}
+
+func printCohorts(size analysis.BucketSize, cohorts []*analysis.Cohort, retention int) {
+	header := []string{"Cohort", "Size"}
+	for n := 1; n <= retention; n++ {
+		header = append(header, "+"+strconv.Itoa(n))
+	}
+	table := markdown.NewTable(header...)
+	for _, cohort := range cohorts {
+		row := []any{size.Label(cohort.Start), cohort.Members.Len()}
+		for n := 1; n <= retention; n++ {
+			if rate, ok := cohort.Retention(n); ok {
+				row = append(row, fmt.Sprintf("%.0f%%", 100*rate))
+			} else {
+				row = append(row, "")
+			}
+		}
+		table.AddRow(row...)
+	}
+	table.Print(os.Stdout)
+}
+
+// writeContributorCSV writes the new and returning contributors in each time
+// bucket as CSV records.
+func writeContributorCSV(w io.Writer, size analysis.BucketSize, summaries []*analysis.ContributorSummary) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{
+		"bucket", "contributors", "new", "returning",
+		"first_prs", "first_prs_merged", "first_prs_closed", "first_pr_median_days_to_merge",
+		"veteran_prs", "veteran_prs_merged", "veteran_prs_closed", "veteran_median_days_to_merge",
+	}); err != nil {
+		return err
+	}
+	for _, summary := range summaries {
+		if err := writer.Write([]string{
+			size.Label(summary.Start),
+			strconv.Itoa(summary.Contributors()),
+			strconv.Itoa(summary.New.Len()),
+			strconv.Itoa(summary.Returning.Len()),
+			strconv.Itoa(summary.FirstPullRequests.Total),
+			strconv.Itoa(summary.FirstPullRequests.Merged),
+			strconv.Itoa(summary.FirstPullRequests.Closed),
+			formatMedianDays(summary.FirstPullRequests.Durations),
+			strconv.Itoa(summary.VeteranPullRequests.Total),
+			strconv.Itoa(summary.VeteranPullRequests.Merged),
+			strconv.Itoa(summary.VeteranPullRequests.Closed),
+			formatMedianDays(summary.VeteranPullRequests.Durations),
+		}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeCohortCSV writes the cohort retention as CSV records, one per cohort
+// and period offset.
+func writeCohortCSV(w io.Writer, size analysis.BucketSize, cohorts []*analysis.Cohort, retention int) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"cohort", "size", "offset", "active", "retention"}); err != nil {
+		return err
+	}
+	for _, cohort := range cohorts {
+		for n := 0; n <= retention && n < len(cohort.Active); n++ {
+			rate, _ := cohort.Retention(n)
+			if err := writer.Write([]string{
+				size.Label(cohort.Start),
+				strconv.Itoa(cohort.Members.Len()),
+				strconv.Itoa(n),
+				strconv.Itoa(cohort.Active[n]),
+				strconv.FormatFloat(rate, 'f', 4, 64),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func formatMedianDays(durations []time.Duration) string {
+	if len(durations) == 0 {
+		return ""
+	}
+	durations = slices.Clone(durations)
+	slices.Sort(durations)
+	return strconv.FormatFloat(math.Median(durations).Hours()/24, 'f', 2, 64)
+}
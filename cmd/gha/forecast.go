@@ -0,0 +1,216 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shizhMSFT/gha/pkg/analysis"
+	"github.com/shizhMSFT/gha/pkg/github"
+	"github.com/shizhMSFT/gha/pkg/markdown"
+	"github.com/shizhMSFT/gha/pkg/math"
+	"github.com/urfave/cli/v3"
+)
+
+// forecastMaxWeeks is the horizon of forecasts, beyond which items are
+// considered never done.
+const forecastMaxWeeks = 520
+
+// forecastConfidences are the confidence levels of forecasts.
+var forecastConfidences = []float64{0.5, 0.85, 0.95}
+
+var forecastCommand = &cli.Command{
+	Name:      "forecast",
+	Usage:     "forecast completion with Monte Carlo simulations of weekly throughput",
+	ArgsUsage: "<snapshot> [...]",
+	Aliases:   []string{"f"},
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:     "history",
+			Usage:    "sample weekly throughput from the complete weeks covering the last `DAYS`",
+			Value:    90,
+			OnlyOnce: true,
+		},
+		&cli.TimestampFlag{
+			Name:     "end-date",
+			Usage:    "sample the history before `DATE` and forecast from it with the items open then, defaulting to now",
+			Config:   cli.TimestampConfig{Layout: time.DateOnly},
+			OnlyOnce: true,
+		},
+		&cli.StringFlag{
+			Name:     "type",
+			Usage:    "count closed `{issue, pr, all}` as throughput, where pull requests count when merged",
+			Value:    "issue",
+			OnlyOnce: true,
+		},
+		&cli.IntFlag{
+			Name:     "items",
+			Usage:    "forecast when `N` items will be done, defaulting to the open items",
+			OnlyOnce: true,
+		},
+		&cli.StringFlag{
+			Name:     "milestone",
+			Usage:    "forecast when the open items in the milestone `TITLE` will be done",
+			OnlyOnce: true,
+		},
+		&cli.TimestampFlag{
+			Name:     "by",
+			Usage:    "forecast how many items will be done by `DATE`",
+			Config:   cli.TimestampConfig{Layout: time.DateOnly},
+			OnlyOnce: true,
+		},
+		&cli.IntFlag{
+			Name:     "runs",
+			Usage:    "number of simulation `RUNS`",
+			Value:    10000,
+			OnlyOnce: true,
+		},
+		&cli.IntFlag{
+			Name:     "seed",
+			Usage:    "random `SEED` of simulations",
+			Value:    1,
+			OnlyOnce: true,
+		},
+	},
+	Action: runForecast,
+}
+
+func runForecast(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return errors.New("no snapshot files specified")
+	}
+
+	// parse flags
+	kind := strings.ToLower(ctx.String("type"))
+	switch kind {
+	case "issue", "pr", "all":
+	default:
+		return fmt.Errorf("invalid type: %s", kind)
+	}
+	history := int(ctx.Int("history"))
+	if history < 7 {
+		return errors.New("history must be at least 7 days")
+	}
+	runs := int(ctx.Int("runs"))
+	if runs <= 0 {
+		return errors.New("runs must be positive")
+	}
+	rng := rand.New(rand.NewSource(ctx.Int("seed")))
+	milestone := ctx.String("milestone")
+	match := func(issue github.Issue) bool {
+		switch kind {
+		case "issue":
+			if issue.IsPullRequest() {
+				return false
+			}
+		case "pr":
+			if !issue.IsPullRequest() {
+				return false
+			}
+		}
+		return milestone == "" || issue.Milestone.Title == milestone
+	}
+
+	// sample the last complete weeks of throughput before the end
+	end := time.Now().UTC()
+	if date := ctx.Value("end-date").(time.Time); !date.IsZero() {
+		end = date
+	}
+	thisWeek := analysis.BucketWeek.Truncate(end)
+	historyFrame := analysis.TimeFrame{
+		Start: thisWeek.AddDate(0, 0, -7*((history+6)/7)),
+		End:   thisWeek.Add(-time.Nanosecond),
+	}
+	buckets := historyFrame.Buckets(analysis.BucketWeek, historyFrame.Start, historyFrame.End)
+	samples := make([]int, len(buckets))
+	open := 0
+	for _, path := range ctx.Args().Slice() {
+		snapshot, err := readIssues(path)
+		if err != nil {
+			return err
+		}
+		// throughput does not depend on milestones
+		throughput := analysis.Filter(snapshot, func(issue github.Issue) bool {
+			return kind == "all" || (kind == "pr") == issue.IsPullRequest()
+		})
+		for i, summary := range analysis.SummarizeThroughput(throughput, buckets, nil) {
+			samples[i] += summary.IssuesClosed + summary.PullRequestsMerged
+		}
+		open += len(analysis.Filter(snapshot, func(issue github.Issue) bool {
+			return openAt(issue, end) && match(issue)
+		}))
+	}
+	items := open
+	if n := ctx.Int("items"); n > 0 {
+		items = int(n)
+	}
+
+	// generate report
+	fmt.Println("Monte Carlo Forecast")
+	fmt.Println("====================")
+	fmt.Println()
+	fmt.Printf("- History: %d weeks from %s to %s\n", len(samples), historyFrame.Start.Format(time.DateOnly), historyFrame.End.Format(time.DateOnly))
+	fmt.Println("- Forecast from:", end.Format(time.DateOnly))
+	sampleTexts := make([]string, 0, len(samples))
+	for _, sample := range samples {
+		sampleTexts = append(sampleTexts, fmt.Sprint(sample))
+	}
+	fmt.Println("- Weekly throughput samples:", strings.Join(sampleTexts, ", "))
+	fmt.Println("- Runs:", runs)
+	if slices.Max(samples) == 0 {
+		fmt.Println()
+		fmt.Println("No throughput in the history to forecast")
+		return nil
+	}
+
+	fmt.Println()
+	if milestone != "" {
+		fmt.Printf("## When will %d items in %s be done?\n", items, milestone)
+	} else {
+		fmt.Printf("## When will %d items be done?\n", items)
+	}
+	fmt.Println()
+	weeks := math.SimulatePeriods(samples, items, runs, forecastMaxWeeks, rng)
+	slices.Sort(weeks)
+	table := markdown.NewTable("Confidence", "Weeks", "Date")
+	for _, confidence := range forecastConfidences {
+		n := math.Percentile(weeks, confidence)
+		if n > forecastMaxWeeks {
+			table.AddRow(fmt.Sprintf("%.0f%%", confidence*100), fmt.Sprintf("> %d", forecastMaxWeeks), "never")
+			continue
+		}
+		table.AddRow(fmt.Sprintf("%.0f%%", confidence*100), n, end.AddDate(0, 0, 7*n).Format(time.DateOnly))
+	}
+	table.Print(os.Stdout)
+
+	if by := ctx.Value("by").(time.Time); !by.IsZero() {
+		fmt.Println()
+		fmt.Println("## How many items will be done by", by.Format(time.DateOnly)+"?")
+		fmt.Println()
+		periods := int(by.Sub(end).Hours() / 24 / 7)
+		if periods <= 0 {
+			fmt.Println("The date is less than a week away")
+			return nil
+		}
+		counts := math.SimulateThroughput(samples, periods, runs, rng)
+		slices.Sort(counts)
+		table := markdown.NewTable("Confidence", "At least")
+		for _, confidence := range forecastConfidences {
+			table.AddRow(fmt.Sprintf("%.0f%%", confidence*100), math.Percentile(counts, 1-confidence))
+		}
+		table.Print(os.Stdout)
+	}
+	return nil
+}
+
+// openAt reports whether the issue was open at the time.
+func openAt(issue github.Issue, t time.Time) bool {
+	if issue.CreatedAt.After(t) {
+		return false
+	}
+	return issue.ClosedAt == nil || issue.ClosedAt.After(t)
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/shizhMSFT/gha/pkg/analysis"
+	"github.com/shizhMSFT/gha/pkg/calendar"
+	"github.com/shizhMSFT/gha/pkg/container/set"
+	"github.com/shizhMSFT/gha/pkg/github"
+	"github.com/shizhMSFT/gha/pkg/markdown"
+	"github.com/urfave/cli/v3"
+	"golang.org/x/exp/maps"
+)
+
+var linksCommand = &cli.Command{
+	Name:      "links",
+	Usage:     "analyze links between issues and the pull requests fixing them",
+	ArgsUsage: "<snapshot> [...]",
+	Aliases:   []string{"l"},
+	Flags: append([]cli.Flag{
+		&cli.IntFlag{
+			Name:     "ago",
+			Usage:    "only include issues and pull requests that are at least `DAYS` old",
+			OnlyOnce: true,
+		},
+		&cli.TimestampFlag{
+			Name:     "start-date",
+			Usage:    "only include issues and pull requests that were created after `DATE`",
+			Config:   cli.TimestampConfig{Layout: time.DateOnly},
+			OnlyOnce: true,
+		},
+		&cli.TimestampFlag{
+			Name:     "end-date",
+			Usage:    "only include issues and pull requests that were created before `DATE`",
+			Config:   cli.TimestampConfig{Layout: time.DateOnly},
+			OnlyOnce: true,
+		},
+		&cli.StringSliceFlag{
+			Name:  "timelines",
+			Usage: "specify timeline `SNAPSHOT` files in the same order as the snapshots to link issues closed by merging cross-referencing pull requests",
+		},
+		calendarFlag(),
+		&cli.BoolFlag{
+			Name:     "list",
+			Usage:    "list issues closed without a fix and pull requests without a linked issue",
+			OnlyOnce: true,
+		},
+	}, botFlags()...),
+	Action: runLinks,
+}
+
+func runLinks(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return errors.New("no snapshot files specified")
+	}
+
+	// parse flags
+	var opts analysis.SummarizeLinksOptions
+	if ago := ctx.Int("ago"); ago > 0 {
+		opts.TimeFrame.Start = time.Now().UTC().AddDate(0, 0, int(-ago))
+	}
+	if date := ctx.Value("start-date").(time.Time); !date.IsZero() {
+		opts.TimeFrame.Start = date
+	}
+	if date := ctx.Value("end-date").(time.Time); !date.IsZero() {
+		opts.TimeFrame.End = date
+	}
+	timelinePaths := ctx.StringSlice("timelines")
+	if len(timelinePaths) > 0 && len(timelinePaths) != ctx.NArg() {
+		return fmt.Errorf("mismatched number of timeline snapshots: %d timeline snapshots for %d snapshots", len(timelinePaths), ctx.NArg())
+	}
+	var err error
+	opts.Calendar, err = parseCalendar(ctx)
+	if err != nil {
+		return err
+	}
+	bots, err := parseBotFilter(ctx)
+	if err != nil {
+		return err
+	}
+	list := ctx.Bool("list")
+
+	// read snapshots
+	snapshots := make([]map[int]github.Issue, 0, ctx.NArg())
+	for _, path := range ctx.Args().Slice() {
+		snapshot, err := readIssues(path)
+		if err != nil {
+			return err
+		}
+		snapshots = append(snapshots, bots.issues(snapshot))
+	}
+	var timelines []map[int][]github.TimelineEvent
+	for _, path := range timelinePaths {
+		snapshot, err := readTimelines(path)
+		if err != nil {
+			return err
+		}
+		timelines = append(timelines, snapshot)
+	}
+	opts.Graph = analysis.BuildLinkGraph(snapshots, timelines)
+
+	// generate report
+	fmt.Println("Issue and Pull Request Links")
+	fmt.Println("============================")
+	printTimeFrame(opts.TimeFrame)
+	printCalendar(opts.Calendar)
+	var summaries []*analysis.LinkSummary
+	for i, path := range ctx.Args().Slice() {
+		snapshotOpts := opts
+		snapshotOpts.Issues = snapshots[i]
+		summary := analysis.SummarizeLinks(snapshotOpts)
+		summaries = append(summaries, summary)
+		fmt.Println()
+		fmt.Println("##", path)
+		printLinkSummary(opts.Calendar, summary)
+		if list {
+			printUnlinked(summary, snapshotOpts.Issues)
+		}
+	}
+	if ctx.NArg() > 1 {
+		fmt.Println()
+		fmt.Println("## Overall")
+		printLinkSummary(opts.Calendar, summaries...)
+	}
+	return nil
+}
+
+func printLinkSummary(cal *calendar.Calendar, summaries ...*analysis.LinkSummary) {
+	var leadTimes []time.Duration
+	var closedIssues, closedWithoutFix, merged, unlinked int
+	links := set.New[analysis.Link]()
+	sources := make(map[string]set.Set[analysis.Link])
+	for _, summary := range summaries {
+		for link := range summary.Links {
+			links.Add(link)
+		}
+		for source, sourceLinks := range summary.Sources {
+			if sources[source] == nil {
+				sources[source] = set.New[analysis.Link]()
+			}
+			for link := range sourceLinks {
+				sources[source].Add(link)
+			}
+		}
+		leadTimes = append(leadTimes, maps.Values(summary.LeadTimes)...)
+		closedIssues += summary.ClosedIssues
+		closedWithoutFix += summary.ClosedWithoutFix.Len()
+		merged += summary.MergedPullRequests
+		unlinked += summary.UnlinkedPullRequests.Len()
+	}
+
+	fmt.Println()
+	fmt.Println("- Links:", links.Len())
+	for _, source := range []string{analysis.LinkClosingReference, analysis.LinkTimeline} {
+		fmt.Printf("  - By %s: %d\n", source, sources[source].Len())
+	}
+	fmt.Println()
+	fmt.Println("### Lead Time from Issue to Merged Fix")
+	fmt.Println()
+	fmt.Println("- Fixed issues:", len(leadTimes))
+	printDurationStats("  ", leadTimes, cal)
+	fmt.Println()
+	fmt.Println("### Unlinked Items")
+	fmt.Println()
+	fmt.Printf("- Closed issues without a merged fix: %d of %d (%s)\n", closedWithoutFix, closedIssues, formatShare(closedWithoutFix, closedIssues))
+	fmt.Printf("- Merged pull requests without a linked issue: %d of %d (%s)\n", unlinked, merged, formatShare(unlinked, merged))
+}
+
+func formatShare(count, total int) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", 100*float64(count)/float64(total))
+}
+
+// printUnlinked lists the issues closed without a fix and the pull requests
+// without a linked issue.
+func printUnlinked(summary *analysis.LinkSummary, issues map[int]github.Issue) {
+	lists := []struct {
+		title   string
+		column  string
+		numbers []int
+	}{
+		{"Closed Issues without a Merged Fix", "#Issue", maps.Keys(summary.ClosedWithoutFix)},
+		{"Merged Pull Requests without a Linked Issue", "#PR", maps.Keys(summary.UnlinkedPullRequests)},
+	}
+	for _, list := range lists {
+		if len(list.numbers) == 0 {
+			continue
+		}
+		slices.Sort(list.numbers)
+		fmt.Println()
+		fmt.Println("###", list.title)
+		fmt.Println()
+		table := markdown.NewTable(list.column, "Author", "Title")
+		for _, number := range list.numbers {
+			issue := issues[number]
+			table.AddRow(fmt.Sprintf("#%d", number), "@"+issue.User.Login, issue.Title)
+		}
+		table.Print(os.Stdout)
+	}
+}
@@ -19,6 +19,19 @@ var app = &cli.Command{
 		reportCommand,
 		pullRequestReviewCommand,
 		issueCommentCommand,
+		reviewMatrixCommand,
+		suggestReviewersCommand,
+		backlogCommand,
+		milestoneCommand,
+		forecastCommand,
+		staleCommand,
+		triageCommand,
+		reopenCommand,
+		contributorsCommand,
+		activityCommand,
+		linksCommand,
+		auditCommand,
+		ownerReviewCommand,
 	},
 }
 
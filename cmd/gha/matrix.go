@@ -0,0 +1,233 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shizhMSFT/gha/pkg/analysis"
+	"github.com/shizhMSFT/gha/pkg/identity"
+	"github.com/shizhMSFT/gha/pkg/markdown"
+	"github.com/urfave/cli/v3"
+)
+
+var reviewMatrixCommand = &cli.Command{
+	Name:      "review-matrix",
+	Usage:     "analyze who reviews whose pull requests",
+	ArgsUsage: "<review_snapshot> [...]",
+	Aliases:   []string{"matrix", "m"},
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:     "ago",
+			Usage:    "only include snapshots that are at least `DAYS` old",
+			OnlyOnce: true,
+		},
+		&cli.TimestampFlag{
+			Name:     "start-date",
+			Usage:    "only include snapshots that were created after `DATE`",
+			Config:   cli.TimestampConfig{Layout: time.DateOnly},
+			OnlyOnce: true,
+		},
+		&cli.TimestampFlag{
+			Name:     "end-date",
+			Usage:    "only include snapshots that were created before `DATE`",
+			Config:   cli.TimestampConfig{Layout: time.DateOnly},
+			OnlyOnce: true,
+		},
+		&cli.StringSliceFlag{
+			Name:     "issues",
+			Usage:    "specify issue `SNAPSHOT` files in the same order as the review snapshots",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:     "identities",
+			Usage:    "resolve accounts to people with an identity `FILE`",
+			OnlyOnce: true,
+		},
+		&cli.StringFlag{
+			Name:     "format",
+			Usage:    "output format of the matrix in `{markdown, csv, dot}`, where csv and dot combine all snapshots",
+			Value:    "markdown",
+			OnlyOnce: true,
+		},
+		botPatternFlag(),
+	},
+	Action: runReviewMatrix,
+}
+
+func runReviewMatrix(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return errors.New("no review snapshot files specified")
+	}
+
+	// parse flags
+	var timeFrame analysis.TimeFrame
+	if ago := ctx.Int("ago"); ago > 0 {
+		timeFrame.Start = time.Now().UTC().AddDate(0, 0, int(-ago))
+	}
+	if date := ctx.Value("start-date").(time.Time); !date.IsZero() {
+		timeFrame.Start = date
+	}
+	if date := ctx.Value("end-date").(time.Time); !date.IsZero() {
+		timeFrame.End = date
+	}
+	issuePaths := ctx.StringSlice("issues")
+	if len(issuePaths) != ctx.NArg() {
+		return fmt.Errorf("mismatched number of issue snapshots: %d issue snapshots for %d review snapshots", len(issuePaths), ctx.NArg())
+	}
+	format := strings.ToLower(ctx.String("format"))
+	switch format {
+	case "markdown", "csv", "dot":
+	default:
+		return fmt.Errorf("invalid format: %s", format)
+	}
+
+	var identities *identity.Map
+	if path := ctx.String("identities"); path != "" {
+		var err error
+		identities, err = readIdentities(path)
+		if err != nil {
+			return err
+		}
+	}
+	bots, err := parseBotClassifier(ctx)
+	if err != nil {
+		return err
+	}
+
+	// build matrices
+	report := analysis.NewPullRequestReviewReport(timeFrame)
+	report.Identities = identities
+	report.Bots = bots
+	for i, path := range ctx.Args().Slice() {
+		reviews, err := readPullRequestReviews(path)
+		if err != nil {
+			return err
+		}
+		issues, err := readIssues(issuePaths[i])
+		if err != nil {
+			return err
+		}
+		report.SummarizeReviewMatrix(path, issues, reviews)
+	}
+
+	// generate report
+	switch format {
+	case "csv":
+		return writeReviewMatrixCSV(os.Stdout, report.ReviewMatrix())
+	case "dot":
+		return writeReviewMatrixDOT(os.Stdout, report.ReviewMatrix())
+	}
+	fmt.Println("Pull Request Review Matrix")
+	fmt.Println("==========================")
+	printTimeFrame(timeFrame)
+	for _, path := range ctx.Args().Slice() {
+		fmt.Println()
+		fmt.Println("##", path)
+		printReviewMatrix(report.Matrices[path])
+	}
+	if ctx.NArg() > 1 {
+		fmt.Println()
+		fmt.Println("## Overall")
+		printReviewMatrix(report.ReviewMatrix())
+	}
+	return nil
+}
+
+// heatLevels are the shades of the heat table from cold to hot.
+var heatLevels = []string{"░", "▒", "▓", "█"}
+
+// printReviewMatrix prints the matrix as a markdown heat table, where each cell
+// shows the reviewed pull request count and its share of the author's pull
+// requests.
+func printReviewMatrix(matrix *analysis.ReviewMatrix) {
+	authors := matrix.SortedAuthors()
+	reviewers := matrix.SortedReviewers()
+	fmt.Println()
+	if len(reviewers) == 0 {
+		fmt.Println("No reviews")
+		return
+	}
+
+	header := append([]string{"Author \\ Reviewer", "PRs"}, reviewers...)
+	table := markdown.NewTable(header...)
+	for _, author := range authors {
+		row := []any{author, matrix.Authors[author]}
+		for _, reviewer := range reviewers {
+			count := matrix.Count(author, reviewer)
+			if count == 0 {
+				row = append(row, "-")
+				continue
+			}
+			share := matrix.Share(author, reviewer)
+			level := min(int(share*float64(len(heatLevels))), len(heatLevels)-1)
+			row = append(row, fmt.Sprintf("%s %d (%.0f%%)", heatLevels[level], count, share*100))
+		}
+		table.AddRow(row...)
+	}
+	table.Print(os.Stdout)
+}
+
+// writeReviewMatrixCSV writes the matrix as CSV records of author-reviewer
+// pairs.
+func writeReviewMatrixCSV(w io.Writer, matrix *analysis.ReviewMatrix) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"author", "reviewer", "count", "share", "author_total"}); err != nil {
+		return err
+	}
+	for _, author := range matrix.SortedAuthors() {
+		for _, reviewer := range matrix.SortedReviewers() {
+			count := matrix.Count(author, reviewer)
+			if count == 0 {
+				continue
+			}
+			if err := writer.Write([]string{
+				author,
+				reviewer,
+				strconv.Itoa(count),
+				strconv.FormatFloat(matrix.Share(author, reviewer), 'f', 4, 64),
+				strconv.Itoa(matrix.Authors[author]),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeReviewMatrixDOT writes the matrix as a Graphviz directed graph with
+// edges from authors to reviewers, weighted by the reviewed pull request
+// counts.
+func writeReviewMatrixDOT(w io.Writer, matrix *analysis.ReviewMatrix) error {
+	maxCount := 0
+	for _, pairs := range matrix.Pairs {
+		for _, count := range pairs {
+			maxCount = max(maxCount, count)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph reviews {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box];\n")
+	for _, author := range matrix.SortedAuthors() {
+		for _, reviewer := range matrix.SortedReviewers() {
+			count := matrix.Count(author, reviewer)
+			if count == 0 {
+				continue
+			}
+			width := 1 + 4*float64(count)/float64(maxCount)
+			fmt.Fprintf(&b, "  %q -> %q [label=\"%d (%.0f%%)\", penwidth=%.2f];\n",
+				author, reviewer, count, matrix.Share(author, reviewer)*100, width)
+		}
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/shizhMSFT/gha/pkg/analysis"
+	"github.com/shizhMSFT/gha/pkg/github"
+	"github.com/shizhMSFT/gha/pkg/markdown"
+	"github.com/urfave/cli/v3"
+)
+
+var milestoneCommand = &cli.Command{
+	Name:      "milestone",
+	Usage:     "report milestone progress and burndown",
+	ArgsUsage: "<issue_snapshot> [<milestone_snapshot>]",
+	Aliases:   []string{"ms"},
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "milestone",
+			Usage:    "only report the milestone `TITLE`",
+			OnlyOnce: true,
+		},
+		&cli.StringFlag{
+			Name:     "timelines",
+			Usage:    "specify a timeline `SNAPSHOT` file to date the items added to milestones by milestoned events",
+			OnlyOnce: true,
+		},
+		&cli.StringFlag{
+			Name:     "bucket",
+			Usage:    "sample the burndown at the end of each `{week, month, quarter}`",
+			Value:    "week",
+			OnlyOnce: true,
+		},
+		&cli.IntFlag{
+			Name:     "grace",
+			Usage:    "report items added more than `DAYS` after the creation of a milestone as scope creep",
+			Value:    7,
+			OnlyOnce: true,
+		},
+		&cli.TimestampFlag{
+			Name:     "planned-by",
+			Usage:    "report items added to any milestone after `DATE` as scope creep, overriding --grace",
+			Config:   cli.TimestampConfig{Layout: time.DateOnly},
+			OnlyOnce: true,
+		},
+	},
+	Action: runMilestone,
+}
+
+func runMilestone(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return errors.New("no snapshot files specified")
+	}
+
+	// parse flags
+	opts := analysis.SummarizeMilestonesOptions{
+		Now:       time.Now().UTC(),
+		PlannedBy: ctx.Value("planned-by").(time.Time),
+	}
+	var err error
+	opts.Bucket, err = analysis.ParseBucketSize(ctx.String("bucket"))
+	if err != nil {
+		return err
+	}
+	grace := ctx.Int("grace")
+	if grace < 0 {
+		return fmt.Errorf("invalid grace period: %d", grace)
+	}
+	opts.Grace = time.Duration(grace) * 24 * time.Hour
+	title := ctx.String("milestone")
+
+	// read snapshots
+	opts.Issues, err = readIssues(ctx.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	if path := ctx.Args().Get(1); path != "" {
+		opts.Milestones, err = readMilestones(path)
+		if err != nil {
+			return err
+		}
+	}
+	if path := ctx.String("timelines"); path != "" {
+		opts.Timelines, err = readTimelines(path)
+		if err != nil {
+			return err
+		}
+	}
+
+	// generate report
+	now := opts.Now
+	summaries := analysis.SummarizeMilestones(opts)
+	fmt.Println("Milestone Report")
+	fmt.Println("================")
+	found := false
+	for _, summary := range summaries {
+		if title != "" && summary.Milestone.Title != title {
+			continue
+		}
+		found = true
+		printMilestoneSummary(summary, now)
+	}
+	if !found {
+		if title != "" {
+			return fmt.Errorf("milestone %q not found", title)
+		}
+		fmt.Println()
+		fmt.Println("No milestones")
+	}
+	return nil
+}
+
+func readMilestones(path string) (map[string]github.Milestone, error) {
+	snapshotJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return github.ParseMilestones(snapshotJSON)
+}
+
+func printMilestoneSummary(summary *analysis.MilestoneSummary, now time.Time) {
+	milestone := summary.Milestone
+	fmt.Println()
+	fmt.Println("##", milestone.Title)
+	fmt.Println()
+	if milestone.State != "" {
+		fmt.Println("- State:", milestone.State)
+	}
+	if !summary.Start.IsZero() {
+		fmt.Println("- Start Date:", summary.Start.Format(time.DateOnly))
+	}
+	if milestone.DueOn != nil {
+		fmt.Println("- Due Date:", milestone.DueOn.Format(time.DateOnly))
+	}
+	fmt.Println("- Total:", summary.Total)
+	fmt.Println("  - Open:", summary.Open)
+	fmt.Println("  - Closed:", summary.Closed)
+	if summary.Total > 0 {
+		fmt.Printf("  - Progress: %.1f%%\n", 100*float64(summary.Closed)/float64(summary.Total))
+	}
+	switch {
+	case summary.Open == 0:
+		if !summary.ProjectedCompletion.IsZero() {
+			fmt.Println("- Completed:", summary.ProjectedCompletion.Format(time.DateOnly))
+		}
+	case summary.ProjectedCompletion.IsZero():
+		fmt.Println("- Projected Completion: unknown, no item closed yet")
+	default:
+		fmt.Printf("- Closure Rate: %.2f items per day\n", summary.ClosureRate)
+		fmt.Println("- Projected Completion:", summary.ProjectedCompletion.Format(time.DateOnly))
+	}
+	if milestone.DueOn != nil && summary.Open > 0 {
+		if summary.Overdue() {
+			if summary.ProjectedCompletion.IsZero() {
+				fmt.Println("- Status: at risk")
+			} else {
+				fmt.Println("- Status: behind schedule by", formatDuration(summary.ProjectedCompletion.Sub(*milestone.DueOn)))
+			}
+		} else {
+			fmt.Println("- Status: on track")
+		}
+		if milestone.DueOn.Before(now) {
+			fmt.Println("- Overdue by", formatDuration(now.Sub(*milestone.DueOn)))
+		}
+	}
+
+	if len(summary.Burndown) > 0 {
+		fmt.Println()
+		fmt.Println("### Burndown")
+		fmt.Println()
+		barSize := 30
+		maxTotal := 0
+		for _, point := range summary.Burndown {
+			maxTotal = max(maxTotal, point.Total)
+		}
+		table := markdown.NewTable("Date", "Total", "Remaining", "Burndown")
+		for _, point := range summary.Burndown {
+			var remaining, closed int
+			if maxTotal > 0 {
+				remaining = point.Remaining * barSize / maxTotal
+				closed = point.Total*barSize/maxTotal - remaining
+			}
+			bar := strings.Repeat(heatLevels[len(heatLevels)-1], remaining) + strings.Repeat(heatLevels[0], closed)
+			table.AddRow(point.Time.Format(time.DateOnly), point.Total, point.Remaining, bar)
+		}
+		table.Print(os.Stdout)
+		fmt.Println()
+		fmt.Printf("%s remaining, %s closed\n", heatLevels[len(heatLevels)-1], heatLevels[0])
+	}
+
+	fmt.Println()
+	switch {
+	case summary.Baseline.IsZero():
+		fmt.Println("### Scope Creep: unknown")
+		fmt.Println()
+		fmt.Println("The creation of the milestone is unknown. Specify a milestone snapshot or --planned-by.")
+	case len(summary.ScopeCreep) == 0:
+		fmt.Println("### Scope Creep: no items added after", summary.Baseline.Format(time.DateOnly))
+	default:
+		fmt.Println("### Scope Creep:", len(summary.ScopeCreep), "items added after", summary.Baseline.Format(time.DateOnly))
+		fmt.Println()
+		table := markdown.NewTable("#Issue", "Added", "State", "Title")
+		for _, issue := range summary.ScopeCreep {
+			table.AddRow(
+				fmt.Sprintf("[#%d](%s)", issue.Number, issue.HTMLURL),
+				summary.Added[issue.Number].Format(time.DateOnly),
+				issue.State,
+				issue.Title,
+			)
+		}
+		table.Print(os.Stdout)
+	}
+}
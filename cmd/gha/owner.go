@@ -0,0 +1,316 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shizhMSFT/gha/pkg/analysis"
+	"github.com/shizhMSFT/gha/pkg/calendar"
+	"github.com/shizhMSFT/gha/pkg/github"
+	"github.com/shizhMSFT/gha/pkg/markdown"
+	"github.com/shizhMSFT/gha/pkg/math"
+	"github.com/shizhMSFT/gha/pkg/owners"
+	"github.com/urfave/cli/v3"
+	"golang.org/x/exp/maps"
+)
+
+var ownerReviewCommand = &cli.Command{
+	Name:      "owner-review",
+	Usage:     "analyze review coverage of merged pull requests by code owners",
+	ArgsUsage: "<issue_snapshot> <review_snapshot> <pr_files_snapshot>",
+	Aliases:   []string{"o"},
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:     "ago",
+			Usage:    "only include pull requests merged at least `DAYS` ago",
+			OnlyOnce: true,
+		},
+		&cli.TimestampFlag{
+			Name:     "start-date",
+			Usage:    "only include pull requests that were merged after `DATE`",
+			Config:   cli.TimestampConfig{Layout: time.DateOnly},
+			OnlyOnce: true,
+		},
+		&cli.TimestampFlag{
+			Name:     "end-date",
+			Usage:    "only include pull requests that were merged before `DATE`",
+			Config:   cli.TimestampConfig{Layout: time.DateOnly},
+			OnlyOnce: true,
+		},
+		&cli.StringFlag{
+			Name:     "owners",
+			Usage:    "specify a CODEOWNERS or OWNERS `FILE` assigning owners to paths",
+			Required: true,
+			OnlyOnce: true,
+		},
+		&cli.StringFlag{
+			Name:     "teams",
+			Usage:    "resolve team owners to members with a team `FILE`",
+			OnlyOnce: true,
+		},
+		calendarFlag(),
+		&cli.IntFlag{
+			Name:     "top",
+			Usage:    "list at most `N` paths without owners, or all if 0",
+			Value:    20,
+			OnlyOnce: true,
+		},
+		&cli.BoolFlag{
+			Name:     "list",
+			Usage:    "list pull requests merged without a review by the owners of some changed paths",
+			OnlyOnce: true,
+		},
+	},
+	Action: runOwnerReview,
+}
+
+func runOwnerReview(ctx *cli.Context) error {
+	if ctx.NArg() < 3 {
+		return errors.New("no issue, review or pull request file snapshot files specified")
+	}
+
+	// parse flags
+	var opts analysis.SummarizeOwnerReviewsOptions
+	if ago := ctx.Int("ago"); ago > 0 {
+		opts.TimeFrame.Start = time.Now().UTC().AddDate(0, 0, int(-ago))
+	}
+	if date := ctx.Value("start-date").(time.Time); !date.IsZero() {
+		opts.TimeFrame.Start = date
+	}
+	if date := ctx.Value("end-date").(time.Time); !date.IsZero() {
+		opts.TimeFrame.End = date
+	}
+	ownersPath := ctx.String("owners")
+	var err error
+	opts.Owners, err = readOwners(ownersPath)
+	if err != nil {
+		return err
+	}
+	if opts.Owners.Format == owners.FormatMaintainers {
+		return fmt.Errorf("%s: maintainer lists do not assign owners to paths", ownersPath)
+	}
+	if path := ctx.String("teams"); path != "" {
+		opts.Teams, err = readTeams(path)
+		if err != nil {
+			return err
+		}
+	}
+	opts.Calendar, err = parseCalendar(ctx)
+	if err != nil {
+		return err
+	}
+	top := int(ctx.Int("top"))
+
+	// read snapshots
+	opts.Issues, err = readIssues(ctx.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	opts.Reviews, err = readPullRequestReviews(ctx.Args().Get(1))
+	if err != nil {
+		return err
+	}
+	opts.Files, err = readPullRequestFiles(ctx.Args().Get(2))
+	if err != nil {
+		return err
+	}
+
+	// generate report
+	summary := analysis.SummarizeOwnerReviews(opts)
+	fmt.Println("Owner Review Coverage")
+	fmt.Println("=====================")
+	printTimeFrame(opts.TimeFrame)
+	printCalendar(opts.Calendar)
+	fmt.Printf("- Owners: %s (%s)\n", ownersPath, opts.Owners.Format)
+	fmt.Println()
+	fmt.Println("- Merged pull requests:", summary.PullRequests)
+	fmt.Printf("  - Reviewed by owners of all changed paths: %d (%s)\n", summary.Covered.Len(), formatShare(summary.Covered.Len(), summary.PullRequests))
+	fmt.Printf("  - Missing owner reviews: %d (%s)\n", summary.Uncovered.Len(), formatShare(summary.Uncovered.Len(), summary.PullRequests))
+	fmt.Printf("  - Changing no owned paths: %d (%s)\n", summary.Ownerless.Len(), formatShare(summary.Ownerless.Len(), summary.PullRequests))
+	if summary.NoFiles.Len() > 0 {
+		fmt.Println("- Merged pull requests without changed files:", summary.NoFiles.Len())
+	}
+
+	printPatternCoverage(summary)
+	printOwnerBottlenecks(summary, opts.Calendar)
+	printUnownedPaths(summary, top)
+	if ctx.Bool("list") {
+		printMissingOwnerReviews(summary, opts.Issues)
+	}
+	return nil
+}
+
+func printPatternCoverage(summary *analysis.OwnerReviewSummary) {
+	fmt.Println()
+	fmt.Println("## Coverage by Pattern")
+	fmt.Println()
+	table := markdown.NewTable("Pattern", "Owners", "PRs", "Reviewed", "Approved")
+	rows := 0
+	for _, coverage := range summary.Patterns {
+		total := coverage.PullRequests.Len()
+		if total == 0 {
+			continue
+		}
+		table.AddRow(
+			"`"+coverage.Rule.Pattern+"`",
+			formatOwners(coverage.Rule.Owners),
+			total,
+			fmt.Sprintf("%d (%s)", coverage.Reviewed.Len(), formatShare(coverage.Reviewed.Len(), total)),
+			fmt.Sprintf("%d (%s)", coverage.Approved.Len(), formatShare(coverage.Approved.Len(), total)),
+		)
+		rows++
+	}
+	if rows == 0 {
+		fmt.Println("No owned paths changed")
+		return
+	}
+	table.Print(os.Stdout)
+}
+
+// printOwnerBottlenecks prints the owner groups by the median time to the
+// first owner review in descending order.
+func printOwnerBottlenecks(summary *analysis.OwnerReviewSummary, cal *calendar.Calendar) {
+	type bottleneck struct {
+		group     *analysis.OwnerGroupLatency
+		durations []time.Duration
+	}
+	var bottlenecks []bottleneck
+	for _, group := range summary.Groups {
+		durations := maps.Values(group.Reviewed)
+		slices.Sort(durations)
+		bottlenecks = append(bottlenecks, bottleneck{group: group, durations: durations})
+	}
+	if len(bottlenecks) == 0 {
+		return
+	}
+	median := func(durations []time.Duration) time.Duration {
+		if len(durations) == 0 {
+			return -1
+		}
+		return math.Median(durations)
+	}
+	slices.SortFunc(bottlenecks, func(a, b bottleneck) int {
+		if c := cmp.Compare(median(b.durations), median(a.durations)); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(b.group.NoReview.Len(), a.group.NoReview.Len()); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.group.Owners, b.group.Owners)
+	})
+
+	fmt.Println()
+	fmt.Println("## Time to Owner Review")
+	fmt.Println()
+	table := markdown.NewTable("Owners", "Patterns", "PRs", "No Owner Review", "Median", "P90", "Max")
+	var unknown bool
+	for _, bottleneck := range bottlenecks {
+		group := bottleneck.group
+		name := group.Owners
+		if group.Unknown {
+			name += " *"
+			unknown = true
+		}
+		medianText, p90Text, maxText := "-", "-", "-"
+		if durations := bottleneck.durations; len(durations) > 0 {
+			medianText = formatWorkingDuration(math.Median(durations), cal)
+			p90Text = formatWorkingDuration(math.Percentile(durations, 0.9), cal)
+			maxText = formatWorkingDuration(math.Max(durations), cal)
+		}
+		table.AddRow(
+			name,
+			group.Patterns.Len(),
+			len(group.Reviewed)+group.NoReview.Len(),
+			group.NoReview.Len(),
+			medianText,
+			p90Text,
+			maxText,
+		)
+	}
+	table.Print(os.Stdout)
+	if unknown {
+		fmt.Println()
+		fmt.Println("\\* Teams without known members. Specify `--teams` to resolve them.")
+	}
+}
+
+// printUnownedPaths prints the changed paths without owners by the number of
+// pull requests changing them.
+func printUnownedPaths(summary *analysis.OwnerReviewSummary, top int) {
+	fmt.Println()
+	fmt.Println("## Paths without Owners")
+	fmt.Println()
+	if len(summary.UnownedPaths) == 0 {
+		fmt.Println("All changed paths have owners")
+		return
+	}
+	paths := maps.Keys(summary.UnownedPaths)
+	slices.SortFunc(paths, func(a, b string) int {
+		if c := cmp.Compare(summary.UnownedPaths[b].Len(), summary.UnownedPaths[a].Len()); c != 0 {
+			return c
+		}
+		return cmp.Compare(a, b)
+	})
+	fmt.Println("- Paths:", len(paths))
+	if top > 0 && len(paths) > top {
+		paths = paths[:top]
+	}
+	fmt.Println()
+	table := markdown.NewTable("Path", "PRs")
+	for _, path := range paths {
+		table.AddRow("`"+path+"`", summary.UnownedPaths[path].Len())
+	}
+	table.Print(os.Stdout)
+}
+
+// printMissingOwnerReviews lists the pull requests merged without a review by
+// the owners of some changed paths.
+func printMissingOwnerReviews(summary *analysis.OwnerReviewSummary, issues map[int]github.Issue) {
+	fmt.Println()
+	fmt.Println("## Pull Requests Missing Owner Reviews")
+	fmt.Println()
+	if summary.Uncovered.Len() == 0 {
+		fmt.Println("No pull requests missing owner reviews")
+		return
+	}
+	numbers := sortedNumbers(maps.Keys(summary.Uncovered))
+	missing := make(map[int][]string)
+	for _, coverage := range summary.Patterns {
+		for number := range coverage.PullRequests {
+			if !coverage.Reviewed.Contains(number) {
+				missing[number] = append(missing[number], "`"+coverage.Rule.Pattern+"`")
+			}
+		}
+	}
+	table := markdown.NewTable("#PR", "Author", "Unreviewed Patterns", "Title")
+	for _, number := range numbers {
+		issue := issues[number]
+		table.AddRow(fmt.Sprintf("#%d", number), "@"+issue.User.Login, strings.Join(missing[number], ", "), issue.Title)
+	}
+	table.Print(os.Stdout)
+}
+
+func formatOwners(list []owners.Owner) string {
+	names := make([]string, 0, len(list))
+	for _, owner := range list {
+		names = append(names, owner.String())
+	}
+	return strings.Join(names, " ")
+}
+
+func readTeams(path string) (owners.Teams, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	teams, err := owners.ParseTeams(content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return teams, nil
+}
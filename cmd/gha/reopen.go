@@ -0,0 +1,210 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/shizhMSFT/gha/pkg/analysis"
+	"github.com/shizhMSFT/gha/pkg/github"
+	"github.com/shizhMSFT/gha/pkg/markdown"
+	"github.com/urfave/cli/v3"
+)
+
+var reopenCommand = &cli.Command{
+	Name:      "reopen",
+	Usage:     "analyze reopen rate and churn of issues and pull requests",
+	ArgsUsage: "<issue_snapshot> <timeline_snapshot> | --series <snapshot> [...]",
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:     "ago",
+			Usage:    "only include items that are at least `DAYS` old",
+			OnlyOnce: true,
+		},
+		&cli.TimestampFlag{
+			Name:     "start-date",
+			Usage:    "only include items that were created after `DATE`",
+			Config:   cli.TimestampConfig{Layout: time.DateOnly},
+			OnlyOnce: true,
+		},
+		&cli.TimestampFlag{
+			Name:     "end-date",
+			Usage:    "only include items that were created before `DATE`",
+			Config:   cli.TimestampConfig{Layout: time.DateOnly},
+			OnlyOnce: true,
+		},
+		&cli.BoolFlag{
+			Name:     "series",
+			Usage:    "detect reopens from a series of issue snapshots in chronological order instead of timelines",
+			OnlyOnce: true,
+		},
+		&cli.IntFlag{
+			Name:     "churn",
+			Usage:    "list items reopened at least `N` times",
+			Value:    2,
+			OnlyOnce: true,
+		},
+	},
+	Action: runReopen,
+}
+
+func runReopen(ctx *cli.Context) error {
+	// parse flags
+	var timeFrame analysis.TimeFrame
+	if ago := ctx.Int("ago"); ago > 0 {
+		timeFrame.Start = time.Now().UTC().AddDate(0, 0, int(-ago))
+	}
+	if date := ctx.Value("start-date").(time.Time); !date.IsZero() {
+		timeFrame.Start = date
+	}
+	if date := ctx.Value("end-date").(time.Time); !date.IsZero() {
+		timeFrame.End = date
+	}
+	churn := int(ctx.Int("churn"))
+	series := ctx.Bool("series")
+
+	// read snapshots
+	var issues map[int]github.Issue
+	var summarize func(map[int]github.Issue) *analysis.ReopenSummary
+	if series {
+		if ctx.NArg() < 2 {
+			return errors.New("at least two issue snapshot files required")
+		}
+		var snapshots []map[int]github.Issue
+		for _, path := range ctx.Args().Slice() {
+			snapshot, err := readIssues(path)
+			if err != nil {
+				return err
+			}
+			snapshots = append(snapshots, snapshot)
+		}
+		issues = snapshots[len(snapshots)-1]
+		summarize = func(issues map[int]github.Issue) *analysis.ReopenSummary {
+			subset := make([]map[int]github.Issue, len(snapshots))
+			for i, snapshot := range snapshots {
+				subset[i] = analysis.Filter(snapshot, func(issue github.Issue) bool {
+					_, ok := issues[issue.Number]
+					return ok
+				})
+			}
+			return analysis.SummarizeReopenSnapshots(subset, timeFrame)
+		}
+	} else {
+		if ctx.NArg() < 2 {
+			return errors.New("no issue or timeline snapshot files specified")
+		}
+		var err error
+		issues, err = readIssues(ctx.Args().Get(0))
+		if err != nil {
+			return err
+		}
+		timelines, err := readTimelines(ctx.Args().Get(1))
+		if err != nil {
+			return err
+		}
+		summarize = func(issues map[int]github.Issue) *analysis.ReopenSummary {
+			return analysis.SummarizeReopens(issues, timelines, timeFrame)
+		}
+	}
+
+	// generate report
+	fmt.Println("Reopen Summary")
+	fmt.Println("==============")
+	printTimeFrame(timeFrame)
+	if series {
+		fmt.Println("- Source:", ctx.NArg(), "snapshots")
+	} else {
+		fmt.Println("- Source: timelines")
+	}
+
+	kinds := []struct {
+		name   string
+		filter func(github.Issue) bool
+	}{
+		{"Issues", func(issue github.Issue) bool { return !issue.IsPullRequest() }},
+		{"Pull Requests", github.Issue.IsPullRequest},
+	}
+	for _, kind := range kinds {
+		items := analysis.Filter(issues, kind.filter)
+		summary := summarize(items)
+		fmt.Println()
+		fmt.Println("##", kind.name)
+		fmt.Println()
+		printReopenSummary(summary)
+		if len(summary.Closed) == 0 {
+			continue
+		}
+
+		// by label
+		labels := make(map[string]struct{})
+		for number := range summary.Closed {
+			for _, label := range items[number].Labels {
+				labels[label.Name] = struct{}{}
+			}
+		}
+		if len(labels) > 0 {
+			type labelRate struct {
+				name    string
+				summary *analysis.ReopenSummary
+			}
+			var rates []labelRate
+			for label := range labels {
+				labeled := analysis.Filter(items, func(issue github.Issue) bool {
+					return hasAnyLabel(issue, []string{label})
+				})
+				rates = append(rates, labelRate{label, summarize(labeled)})
+			}
+			slices.SortFunc(rates, func(a, b labelRate) int {
+				if c := cmp.Compare(b.summary.Rate(), a.summary.Rate()); c != 0 {
+					return c
+				}
+				return cmp.Compare(a.name, b.name)
+			})
+			fmt.Println()
+			fmt.Println("### By Label")
+			fmt.Println()
+			table := markdown.NewTable("Label", "Closed", "Reopened", "Rate")
+			for _, rate := range rates {
+				table.AddRow(rate.name, len(rate.summary.Closed), len(rate.summary.Reopened), fmt.Sprintf("%.1f%%", 100*rate.summary.Rate()))
+			}
+			table.Print(os.Stdout)
+		}
+
+		// churn
+		if churned := summary.Churned(churn); len(churned) > 0 {
+			fmt.Println()
+			fmt.Println("### Reopened at Least", churn, "Times")
+			fmt.Println()
+			table := markdown.NewTable("#", "Reopens", "State", "Title")
+			for _, number := range churned {
+				issue := items[number]
+				table.AddRow(
+					fmt.Sprintf("[#%d](%s)", number, issue.HTMLURL),
+					summary.Reopened[number],
+					issue.State,
+					issue.Title,
+				)
+			}
+			table.Print(os.Stdout)
+		}
+	}
+	return nil
+}
+
+func printReopenSummary(summary *analysis.ReopenSummary) {
+	reopens := 0
+	for _, count := range summary.Reopened {
+		reopens += count
+	}
+	fmt.Println("- Closed:", len(summary.Closed))
+	fmt.Println("- Reopened:", len(summary.Reopened))
+	fmt.Printf("  - Reopen rate: %.1f%%\n", 100*summary.Rate())
+	fmt.Println("  - Total reopens:", reopens)
+	if durations := summary.Durations(); len(durations) > 0 {
+		fmt.Println("  - Time to reopen:")
+		printDurationStats("    ", durations, nil)
+	}
+}
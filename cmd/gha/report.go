@@ -6,14 +6,18 @@ import (
 	"fmt"
 	"os"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/shizhMSFT/gha/pkg/analysis"
+	"github.com/shizhMSFT/gha/pkg/calendar"
 	"github.com/shizhMSFT/gha/pkg/github"
+	"github.com/shizhMSFT/gha/pkg/identity"
 	"github.com/shizhMSFT/gha/pkg/markdown"
 	"github.com/shizhMSFT/gha/pkg/math"
 	"github.com/shizhMSFT/gha/pkg/sort"
 	"github.com/urfave/cli/v3"
+	"gopkg.in/yaml.v3"
 )
 
 var reportCommand = &cli.Command{
@@ -21,7 +25,7 @@ var reportCommand = &cli.Command{
 	Usage:     "generate a report from snapshots",
 	ArgsUsage: "<snapshot> [...]",
 	Aliases:   []string{"r", "summarize"},
-	Flags: []cli.Flag{
+	Flags: append([]cli.Flag{
 		&cli.IntFlag{
 			Name:     "ago",
 			Usage:    "only include snapshots that are at least `DAYS` old",
@@ -44,6 +48,15 @@ var reportCommand = &cli.Command{
 			Usage:    "include contributors from the report",
 			OnlyOnce: true,
 		},
+		&cli.BoolFlag{
+			Name:     "concentration",
+			Usage:    "include bus factor, Gini coefficient and top contributor share of authorship and reviewing",
+			OnlyOnce: true,
+		},
+		&cli.StringSliceFlag{
+			Name:  "reviews",
+			Usage: "specify review `SNAPSHOT` files in the same order as the snapshots to measure the concentration of reviewing",
+		},
 		&cli.IntFlag{
 			Name:     "issue-sla",
 			Usage:    "report issues that were open for more than `DAYS`",
@@ -54,7 +67,48 @@ var reportCommand = &cli.Command{
 			Usage:    "report pull requests that were open for more than `DAYS`",
 			OnlyOnce: true,
 		},
-	},
+		calendarFlag(),
+		&cli.IntSliceFlag{
+			Name:  "close-within",
+			Usage: "report the probability of closing issues and merging pull requests within `DAYS`",
+			Value: []int64{7, 30},
+		},
+		&cli.StringFlag{
+			Name:     "group-by",
+			Usage:    "break down issues and pull requests by `{label, org}`, where org requires identities",
+			OnlyOnce: true,
+		},
+		&cli.StringFlag{
+			Name:     "identities",
+			Usage:    "resolve accounts to people and organizations with an identity `FILE`",
+			OnlyOnce: true,
+		},
+		&cli.StringFlag{
+			Name:     "label-prefix",
+			Usage:    "group by labels with `PREFIX`, such as kind/, and strip the prefix",
+			OnlyOnce: true,
+		},
+		&cli.StringFlag{
+			Name:     "label-map",
+			Usage:    "group by categories in a YAML `FILE` mapping each category to a list of labels",
+			OnlyOnce: true,
+		},
+		&cli.StringFlag{
+			Name:     "compare-to",
+			Usage:    "compare with the baseline period `START..END` in the format of YYYY-MM-DD",
+			OnlyOnce: true,
+		},
+		&cli.IntFlag{
+			Name:     "baseline-ago",
+			Usage:    "compare with the baseline period of the same length `DAYS` earlier",
+			OnlyOnce: true,
+		},
+		&cli.StringFlag{
+			Name:     "bucket",
+			Usage:    "report trends in time buckets of `{week, month, quarter}`",
+			OnlyOnce: true,
+		},
+	}, botFlags()...),
 	Action: runReport,
 }
 
@@ -62,12 +116,61 @@ func runReport(ctx *cli.Context) error {
 	if ctx.NArg() == 0 {
 		return errors.New("no snapshot files specified")
 	}
+	if err := validateReportFlags(ctx); err != nil {
+		return err
+	}
 
 	// parse flags
 	opts := printSummaryOptions{
 		includeContributors: ctx.Bool("contributors"),
 		issueSLA:            int(ctx.Int("issue-sla")),
 		pullRequestSLA:      int(ctx.Int("pr-sla")),
+		closeWithin:         ctx.IntSlice("close-within"),
+		concentration:       ctx.Bool("concentration"),
+	}
+	reviewPaths := ctx.StringSlice("reviews")
+	if len(reviewPaths) > 0 && len(reviewPaths) != ctx.NArg() {
+		return fmt.Errorf("mismatched number of review snapshots: %d review snapshots for %d snapshots", len(reviewPaths), ctx.NArg())
+	}
+	var identities *identity.Map
+	if path := ctx.String("identities"); path != "" {
+		var err error
+		identities, err = readIdentities(path)
+		if err != nil {
+			return err
+		}
+	}
+	switch groupBy := ctx.String("group-by"); groupBy {
+	case "":
+	case "label":
+		categorizer := &analysis.LabelCategorizer{
+			Prefix: ctx.String("label-prefix"),
+		}
+		if path := ctx.String("label-map"); path != "" {
+			categories, err := readLabelMap(path)
+			if err != nil {
+				return err
+			}
+			categorizer.Categories = categories
+		}
+		opts.group = func(issue github.Issue) []string {
+			return categorizer.Categorize(issue.Labels)
+		}
+		opts.groupName = "Label"
+	case "org":
+		if identities == nil {
+			return errors.New("grouping by org requires identities")
+		}
+		opts.group = func(issue github.Issue) []string {
+			if org := identities.Organization(issue.User.Login, issue.CreatedAt); org != "" {
+				return []string{org}
+			}
+			return nil
+		}
+		opts.groupName = "Organization"
+		opts.diversity = true
+	default:
+		return fmt.Errorf("invalid group: %s", groupBy)
 	}
 	if ago := ctx.Int("ago"); ago > 0 {
 		opts.timeFrame.Start = time.Now().UTC().AddDate(0, 0, int(-ago))
@@ -79,30 +182,191 @@ func runReport(ctx *cli.Context) error {
 		opts.timeFrame.End = date
 	}
 
+	bots, err := parseBotFilter(ctx)
+	if err != nil {
+		return err
+	}
+	opts.calendar, err = parseCalendar(ctx)
+	if err != nil {
+		return err
+	}
+
+	if ctx.String("bucket") != "" {
+		return runReportBuckets(ctx, opts.timeFrame, bots, opts.calendar)
+	}
+	if ctx.String("compare-to") != "" || ctx.Int("baseline-ago") > 0 {
+		return runReportComparison(ctx, opts.timeFrame, bots, opts.calendar)
+	}
+
 	// generate report
 	fmt.Println("GitHub Analysis Report")
 	fmt.Println("======================")
 	printTimeFrame(opts.timeFrame)
+	printCalendar(opts.calendar)
 	report := analysis.NewReport(opts.timeFrame)
-	for _, path := range ctx.Args().Slice() {
+	report.Identities = identities
+	report.Calendar = opts.calendar
+	reviewReport := analysis.NewPullRequestReviewReport(opts.timeFrame)
+	reviewReport.Identities = identities
+	groups := make(map[string]*analysis.RepositorySummary)
+	activity := newBotActivity(bots.classifier, opts.timeFrame)
+	for i, path := range ctx.Args().Slice() {
 		fmt.Println()
 		fmt.Println("##", path)
 		snapshot, err := readIssues(path)
 		if err != nil {
 			return err
 		}
+		activity.addIssues(snapshot)
+		snapshot = bots.issues(snapshot)
 		printOpts := opts
 		printOpts.snapshot = snapshot
+		if len(reviewPaths) > 0 {
+			reviews, err := readPullRequestReviews(reviewPaths[i])
+			if err != nil {
+				return err
+			}
+			reviews = filterActivities(bots, reviews, snapshot, reviewer)
+			printOpts.reviewCounts = reviewReport.Summarize(path, snapshot, reviews).ReviewCount()
+		}
+		if opts.group != nil {
+			printOpts.groups = analysis.SummarizeGroups(snapshot, opts.timeFrame, opts.calendar, opts.group)
+			analysis.UnionGroups(groups, printOpts.groups)
+		}
 		printSummary(report.Summarize(path, snapshot), printOpts)
 	}
 	if ctx.NArg() > 1 {
 		fmt.Println()
 		fmt.Println("## Overall")
-		printSummary(report.Abstract(), opts)
+		printOpts := opts
+		printOpts.groups = groups
+		if len(reviewPaths) > 0 {
+			printOpts.reviewCounts = reviewReport.ReviewCount()
+		}
+		printSummary(report.Abstract(), printOpts)
+	}
+	if bots.exclude {
+		activity.print()
+	}
+	return nil
+}
+
+func readLabelMap(path string) (map[string][]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var categories map[string][]string
+	if err := yaml.Unmarshal(content, &categories); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return categories, nil
+}
+
+func readIdentities(path string) (*identity.Map, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	identities, err := identity.Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return identities, nil
+}
+
+// validateReportFlags rejects combinations of flags where some flags would be
+// ignored.
+func validateReportFlags(ctx *cli.Context) error {
+	if ctx.String("group-by") != "label" {
+		for _, name := range []string{"label-prefix", "label-map"} {
+			if ctx.IsSet(name) {
+				return fmt.Errorf("--%s requires --group-by label", name)
+			}
+		}
+	}
+	if ctx.IsSet("label-prefix") && ctx.IsSet("label-map") {
+		return errors.New("--label-prefix and --label-map are mutually exclusive")
+	}
+
+	var mode string
+	switch {
+	case ctx.IsSet("bucket") && (ctx.IsSet("compare-to") || ctx.IsSet("baseline-ago")):
+		return errors.New("--bucket and --compare-to or --baseline-ago are mutually exclusive")
+	case ctx.IsSet("bucket"):
+		mode = "--bucket"
+	case ctx.IsSet("compare-to"):
+		mode = "--compare-to"
+	case ctx.IsSet("baseline-ago"):
+		mode = "--baseline-ago"
+	default:
+		return nil
+	}
+	for _, name := range []string{"group-by", "contributors", "concentration", "reviews", "identities", "issue-sla", "pr-sla", "close-within"} {
+		if ctx.IsSet(name) {
+			return fmt.Errorf("--%s is not supported with %s", name, mode)
+		}
+	}
+	return nil
+}
+
+func runReportBuckets(ctx *cli.Context, timeFrame analysis.TimeFrame, bots *botFilter, cal *calendar.Calendar) error {
+	snapshots := make([]map[int]github.Issue, 0, ctx.NArg())
+	for _, path := range ctx.Args().Slice() {
+		snapshot, err := readIssues(path)
+		if err != nil {
+			return err
+		}
+		snapshots = append(snapshots, bots.issues(snapshot))
+	}
+	size, buckets, err := parseBuckets(ctx, timeFrame, firstCreated(snapshots...))
+	if err != nil {
+		return err
+	}
+
+	// generate report
+	fmt.Println("GitHub Analysis Trends")
+	fmt.Println("======================")
+	printTimeFrame(timeFrame)
+	printCalendar(cal)
+	fmt.Println("- Bucket:", size)
+	overall := make([]*analysis.ThroughputSummary, len(buckets))
+	for i, bucket := range buckets {
+		overall[i] = &analysis.ThroughputSummary{TimeFrame: bucket}
+	}
+	for i, path := range ctx.Args().Slice() {
+		fmt.Println()
+		fmt.Println("##", path)
+		summaries := analysis.SummarizeThroughput(snapshots[i], buckets, cal)
+		for j, summary := range summaries {
+			overall[j].Union(summary)
+		}
+		printThroughputTable(size, summaries, cal)
+	}
+	if ctx.NArg() > 1 {
+		fmt.Println()
+		fmt.Println("## Overall")
+		printThroughputTable(size, overall, cal)
 	}
 	return nil
 }
 
+func printThroughputTable(size analysis.BucketSize, summaries []*analysis.ThroughputSummary, cal *calendar.Calendar) {
+	table := newTrendTable("Bucket", "Issues Opened", "Issues Closed", "Median Time to Close", "PRs Opened", "PRs Merged", "PRs Closed", "Median Time to Merge")
+	for _, summary := range summaries {
+		table.AddBucket(size.Label(summary.Start),
+			countCell(summary.IssuesOpened),
+			countCell(summary.IssuesClosed),
+			medianCell(summary.IssueDurations, cal),
+			countCell(summary.PullRequestsOpened),
+			countCell(summary.PullRequestsMerged),
+			countCell(summary.PullRequestsClosed),
+			medianCell(summary.PullRequestDurations, cal),
+		)
+	}
+	table.Print()
+}
+
 func readIssues(path string) (map[int]github.Issue, error) {
 	snapshotJSON, err := os.ReadFile(path)
 	if err != nil {
@@ -126,6 +390,14 @@ type printSummaryOptions struct {
 	snapshot            map[int]github.Issue
 	issueSLA            int
 	pullRequestSLA      int
+	closeWithin         []int64
+	group               func(github.Issue) []string
+	groupName           string
+	groups              map[string]*analysis.RepositorySummary
+	diversity           bool // whether to measure the diversity of the groups
+	concentration       bool
+	reviewCounts        map[string]int // reviewed pull request counts by reviewer
+	calendar            *calendar.Calendar
 }
 
 func printSummary(summary *analysis.Summary, opts printSummaryOptions) {
@@ -134,10 +406,81 @@ func printSummary(summary *analysis.Summary, opts printSummaryOptions) {
 	if opts.includeContributors {
 		fmt.Println()
 		fmt.Println("### Contributors")
-		printContributors(summary.Authors)
+		printContributors(summary.Authors, opts.calendar)
+	}
+
+	if opts.concentration {
+		fmt.Println()
+		fmt.Println("### Concentration")
+		printConcentration(summary, opts.reviewCounts)
+	}
+
+	if opts.group != nil {
+		fmt.Println()
+		fmt.Printf("### Breakdown by %s\n", opts.groupName)
+		fmt.Println()
+		fmt.Println("#### Issues")
+		printIssueSummaryTable(opts.groupName, opts.groups, opts.calendar)
+
+		fmt.Println()
+		fmt.Println("#### Pull Requests")
+		printPullRequestSummaryTable(opts.groupName, opts.groups, opts.calendar)
+
+		if opts.diversity {
+			fmt.Println()
+			fmt.Println("#### Diversity")
+			printDiversity(opts.groupName, opts.groups)
+		}
 	}
 }
 
+// printDiversity prints how the activities of the groups concentrate on the
+// top group. Items without a group are not counted.
+func printDiversity(name string, groups map[string]*analysis.RepositorySummary) {
+	fmt.Println()
+	table := markdown.NewTable("Activity", "Total", name+"s", "Top "+name, "Top Share", "Gini")
+	addRow := func(activity string, count func(*analysis.RepositorySummary) int) {
+		counts := make(map[string]int)
+		var top string
+		for group, summary := range groups {
+			if group == analysis.NoGroup {
+				continue
+			}
+			counts[group] = count(summary)
+			if counts[group] > counts[top] || (counts[group] == counts[top] && group < top) {
+				top = group
+			}
+		}
+		c := analysis.NewConcentration(counts)
+		if c.Total == 0 {
+			table.AddRow(activity, 0, 0, "-", "-", "-")
+			return
+		}
+		table.AddRow(activity, c.Total, c.Contributors, top, formatShare(counts[top], c.Total), fmt.Sprintf("%.2f", c.Gini))
+	}
+	addRow("Issues", func(summary *analysis.RepositorySummary) int { return summary.Issue.Total })
+	addRow("PRs", func(summary *analysis.RepositorySummary) int { return summary.PullRequest.Total })
+	addRow("Merged PRs", func(summary *analysis.RepositorySummary) int { return summary.PullRequest.Merged })
+	table.Print(os.Stdout)
+	fmt.Println()
+	fmt.Printf("Items without a known %s are not counted.\n", strings.ToLower(name))
+}
+
+func printConcentration(summary *analysis.Summary, reviewCounts map[string]int) {
+	fmt.Println()
+	table := markdown.NewTable("Activity", "Total", "People", "Bus Factor", "Gini", fmt.Sprintf("Top-%d Share", analysis.TopContributors))
+	addRow := func(activity string, c *analysis.Concentration) {
+		table.AddRow(activity, c.Total, c.Contributors, c.BusFactor, fmt.Sprintf("%.2f", c.Gini), fmt.Sprintf("%.1f%%", 100*c.TopShare))
+	}
+	addRow("Merged PRs", analysis.NewConcentration(summary.MergedPullRequests()))
+	if reviewCounts != nil {
+		addRow("Reviewed PRs", analysis.NewConcentration(reviewCounts))
+	}
+	table.Print(os.Stdout)
+	fmt.Println()
+	fmt.Printf("Bus factor is the fewest people covering %.0f%% of the activity.\n", 100*analysis.BusFactorShare)
+}
+
 func printRepositorySummary(summary *analysis.RepositorySummary, opts printSummaryOptions) {
 	fmt.Println()
 	fmt.Println("### Issues")
@@ -154,16 +497,10 @@ func printIssueSummary(summary *analysis.IssueSummary, opts printSummaryOptions)
 	fmt.Println("  - Open:", summary.Open)
 	fmt.Println("  - Closed:", summary.Closed)
 	if len(summary.Durations) > 0 {
-		slices.Sort(summary.Durations)
 		fmt.Println("- Time to close:")
-		fmt.Println("  - Min:", formatDuration(math.Min(summary.Durations)))
-		fmt.Println("  - Max:", formatDuration(math.Max(summary.Durations)))
-		fmt.Println("  - Mean:", formatDuration(math.Mean(summary.Durations)))
-		fmt.Println("  - Median:", formatDuration(math.Median(summary.Durations)))
-		fmt.Println("  - 90th percentile:", formatDuration(math.Percentile(summary.Durations, 0.9)))
-		fmt.Println("  - 95th percentile:", formatDuration(math.Percentile(summary.Durations, 0.95)))
-		fmt.Println("  - 99th percentile:", formatDuration(math.Percentile(summary.Durations, 0.99)))
+		printDurationStats("  ", summary.Durations, opts.calendar)
 	}
+	printSurvival("close", summary.Durations, summary.OpenDurations, nil, opts.closeWithin, opts.calendar)
 	if opts.issueSLA > 0 && opts.snapshot != nil {
 		fmt.Println()
 		issues := analysis.Filter(opts.snapshot, func(issue github.Issue) bool {
@@ -176,21 +513,21 @@ func printIssueSummary(summary *analysis.IssueSummary, opts printSummaryOptions)
 			if issue.State != "closed" {
 				return false
 			}
-			duration := issue.Duration()
-			return duration > time.Duration(opts.issueSLA)*time.Hour*24
+			duration := analysis.IssueDuration(issue, opts.calendar)
+			return duration > days(int64(opts.issueSLA), opts.calendar)
 		})
 		if len(issues) == 0 {
-			fmt.Println("No issues out of SLA:", opts.issueSLA, "days")
+			fmt.Println("No issues out of SLA:", opts.issueSLA, dayUnit(opts.calendar))
 		} else {
-			fmt.Println(len(issues), "issues out of SLA:", opts.issueSLA, "days")
+			fmt.Println(len(issues), "issues out of SLA:", opts.issueSLA, dayUnit(opts.calendar))
 			sortedIssues := sort.SliceFromMap(issues).Sort(func(a, b sort.MapEntry[int, github.Issue]) int {
-				return cmp.Compare(b.Value.Duration(), a.Value.Duration())
+				return cmp.Compare(analysis.IssueDuration(b.Value, opts.calendar), analysis.IssueDuration(a.Value, opts.calendar))
 			})
 			table := markdown.NewTable("#Issue", "Duration", "Title")
 			for _, issue := range sortedIssues {
 				table.AddRow(
 					fmt.Sprintf("#%d", issue.Value.Number),
-					formatDuration(issue.Value.Duration()),
+					formatWorkingDuration(analysis.IssueDuration(issue.Value, opts.calendar), opts.calendar),
 					issue.Value.Title,
 				)
 			}
@@ -207,16 +544,10 @@ func printPullRequestSummary(summary *analysis.PullRequestSummary, opts printSum
 	fmt.Println("  - Closed:", summary.Closed)
 	fmt.Println("  - Merged:", summary.Merged)
 	if len(summary.Durations) > 0 {
-		slices.Sort(summary.Durations)
 		fmt.Println("- Time to merge:")
-		fmt.Println("  - Min:", formatDuration(math.Min(summary.Durations)))
-		fmt.Println("  - Max:", formatDuration(math.Max(summary.Durations)))
-		fmt.Println("  - Mean:", formatDuration(math.Mean(summary.Durations)))
-		fmt.Println("  - Median:", formatDuration(math.Median(summary.Durations)))
-		fmt.Println("  - 90th percentile:", formatDuration(math.Percentile(summary.Durations, 0.9)))
-		fmt.Println("  - 95th percentile:", formatDuration(math.Percentile(summary.Durations, 0.95)))
-		fmt.Println("  - 99th percentile:", formatDuration(math.Percentile(summary.Durations, 0.99)))
+		printDurationStats("  ", summary.Durations, opts.calendar)
 	}
+	printSurvival("merge", summary.Durations, summary.OpenDurations, summary.ClosedDurations, opts.closeWithin, opts.calendar)
 	if opts.pullRequestSLA > 0 && opts.snapshot != nil {
 		fmt.Println()
 		pullRequests := analysis.Filter(opts.snapshot, func(issue github.Issue) bool {
@@ -232,19 +563,19 @@ func printPullRequestSummary(summary *analysis.PullRequestSummary, opts printSum
 			if !issue.Merged() {
 				return false
 			}
-			duration := issue.Duration()
-			return duration > time.Duration(opts.pullRequestSLA)*time.Hour*24
+			duration := analysis.IssueDuration(issue, opts.calendar)
+			return duration > days(int64(opts.pullRequestSLA), opts.calendar)
 		})
 		if len(pullRequests) == 0 {
-			fmt.Println("No pull requests out of SLA:", opts.pullRequestSLA, "days")
+			fmt.Println("No pull requests out of SLA:", opts.pullRequestSLA, dayUnit(opts.calendar))
 		} else {
-			fmt.Println(len(pullRequests), "pull requests out of SLA:", opts.pullRequestSLA, "days")
+			fmt.Println(len(pullRequests), "pull requests out of SLA:", opts.pullRequestSLA, dayUnit(opts.calendar))
 			sortedPullRequests := sort.SliceFromMap(pullRequests).Sort(func(a, b sort.MapEntry[int, github.Issue]) int {
-				return cmp.Compare(b.Value.Duration(), a.Value.Duration())
+				return cmp.Compare(analysis.IssueDuration(b.Value, opts.calendar), analysis.IssueDuration(a.Value, opts.calendar))
 			})
 			table := markdown.NewTable("#PR", "Duration", "Title")
 			for _, pullRequest := range sortedPullRequests {
-				table.AddRow(pullRequest.Value.Number, formatDuration(pullRequest.Value.Duration()), pullRequest.Value.Title)
+				table.AddRow(pullRequest.Value.Number, formatWorkingDuration(analysis.IssueDuration(pullRequest.Value, opts.calendar), opts.calendar), pullRequest.Value.Title)
 			}
 			fmt.Println()
 			table.Print(os.Stdout)
@@ -252,17 +583,64 @@ func printPullRequestSummary(summary *analysis.PullRequestSummary, opts printSum
 	}
 }
 
-func printContributors(authors map[string]*analysis.RepositorySummary) {
+// printSurvival compares the naive statistics of the time to the event, which
+// only count items with the event, with the Kaplan-Meier estimates, which also
+// count open items and items closed without the event as right-censored.
+// Censoring items closed without the event assumes they would have reached it
+// at the same rate, so the estimates are upper bounds with competing risks.
+func printSurvival(event string, durations, openDurations, closedDurations []time.Duration, within []int64, cal *calendar.Calendar) {
+	if len(durations) == 0 {
+		return
+	}
+	if len(openDurations) == 0 && len(closedDurations) == 0 && len(within) == 0 {
+		return
+	}
+	slices.Sort(durations)
+	survival := math.KaplanMeier(durations, append(slices.Clone(openDurations), closedDurations...))
+	formatEstimate := func(p float64) string {
+		if d, ok := survival.Percentile(p); ok {
+			return formatWorkingDuration(d, cal)
+		}
+		return "not reached"
+	}
+
+	fmt.Printf("- Time to %s with %d open items censored", event, len(openDurations))
+	if len(closedDurations) > 0 {
+		fmt.Printf(" and %d items closed without %s censored", len(closedDurations), event)
+	}
+	fmt.Println(":")
+	fmt.Println()
+	table := markdown.NewTable("Metric", "Naive", "Kaplan-Meier")
+	table.AddRow("Items", len(durations), len(durations)+len(openDurations)+len(closedDurations))
+	table.AddRow("Median", formatWorkingDuration(math.Median(durations), cal), formatEstimate(0.5))
+	table.AddRow("90th percentile", formatWorkingDuration(math.Percentile(durations, 0.9), cal), formatEstimate(0.9))
+	for _, n := range within {
+		limit := days(n, cal)
+		count, _ := slices.BinarySearch(durations, limit+1)
+		table.AddRow(
+			fmt.Sprintf("P(%s within %d %s)", event, n, dayUnit(cal)),
+			fmt.Sprintf("%.1f%%", 100*float64(count)/float64(len(durations))),
+			fmt.Sprintf("%.1f%%", 100*survival.CDF(limit)),
+		)
+	}
+	table.Print(os.Stdout)
+	if len(closedDurations) > 0 {
+		fmt.Println()
+		fmt.Printf("Items closed without %s are censored, so the Kaplan-Meier estimates are upper bounds.\n", event)
+	}
+}
+
+func printContributors(authors map[string]*analysis.RepositorySummary, cal *calendar.Calendar) {
 	fmt.Println()
 	fmt.Println("#### Issues")
-	printIssueSummaryTable(authors)
+	printIssueSummaryTable("Author", authors, cal)
 
 	fmt.Println()
 	fmt.Println("#### Pull Requests")
-	printPullRequestSummaryTable(authors)
+	printPullRequestSummaryTable("Author", authors, cal)
 }
 
-func printIssueSummaryTable(authors map[string]*analysis.RepositorySummary) {
+func printIssueSummaryTable(name string, authors map[string]*analysis.RepositorySummary, cal *calendar.Calendar) {
 	// sort by issue counts
 	issueCounts := make(map[string]int)
 	for author, summary := range authors {
@@ -276,7 +654,7 @@ func printIssueSummaryTable(authors map[string]*analysis.RepositorySummary) {
 	})
 
 	// print table
-	table := markdown.NewTable("Author", "Total", "Open", "Closed", "Min", "Max", "Mean", "Median", "P90")
+	table := markdown.NewTable(name, "Total", "Open", "Closed", "Min", "Max", "Mean", "Median", "P90")
 	for _, entry := range counts {
 		author := entry.Key
 		summary := authors[author].Issue
@@ -287,18 +665,18 @@ func printIssueSummaryTable(authors map[string]*analysis.RepositorySummary) {
 
 		slices.Sort(summary.Durations)
 		table.AddRow(author, summary.Total, summary.Open, summary.Closed,
-			formatDuration(math.Min(summary.Durations)),
-			formatDuration(math.Max(summary.Durations)),
-			formatDuration(math.Mean(summary.Durations)),
-			formatDuration(math.Median(summary.Durations)),
-			formatDuration(math.Percentile(summary.Durations, 0.9)),
+			formatWorkingDuration(math.Min(summary.Durations), cal),
+			formatWorkingDuration(math.Max(summary.Durations), cal),
+			formatWorkingDuration(math.Mean(summary.Durations), cal),
+			formatWorkingDuration(math.Median(summary.Durations), cal),
+			formatWorkingDuration(math.Percentile(summary.Durations, 0.9), cal),
 		)
 	}
 	fmt.Println()
 	table.Print(os.Stdout)
 }
 
-func printPullRequestSummaryTable(authors map[string]*analysis.RepositorySummary) {
+func printPullRequestSummaryTable(name string, authors map[string]*analysis.RepositorySummary, cal *calendar.Calendar) {
 	// sort by pull request counts
 	prCounts := make(map[string]int)
 	for author, summary := range authors {
@@ -312,7 +690,7 @@ func printPullRequestSummaryTable(authors map[string]*analysis.RepositorySummary
 	})
 
 	// print table
-	table := markdown.NewTable("Author", "Total", "Open", "Closed", "Merged", "Min", "Max", "Mean", "Median", "P90")
+	table := markdown.NewTable(name, "Total", "Open", "Closed", "Merged", "Min", "Max", "Mean", "Median", "P90")
 	for _, entry := range counts {
 		author := entry.Key
 		summary := authors[author].PullRequest
@@ -323,17 +701,33 @@ func printPullRequestSummaryTable(authors map[string]*analysis.RepositorySummary
 
 		slices.Sort(summary.Durations)
 		table.AddRow(author, summary.Total, summary.Open, summary.Closed, summary.Merged,
-			formatDuration(math.Min(summary.Durations)),
-			formatDuration(math.Max(summary.Durations)),
-			formatDuration(math.Mean(summary.Durations)),
-			formatDuration(math.Median(summary.Durations)),
-			formatDuration(math.Percentile(summary.Durations, 0.9)),
+			formatWorkingDuration(math.Min(summary.Durations), cal),
+			formatWorkingDuration(math.Max(summary.Durations), cal),
+			formatWorkingDuration(math.Mean(summary.Durations), cal),
+			formatWorkingDuration(math.Median(summary.Durations), cal),
+			formatWorkingDuration(math.Percentile(summary.Durations, 0.9), cal),
 		)
 	}
 	fmt.Println()
 	table.Print(os.Stdout)
 }
 
+// printDurationStats prints the statistics of durations as list items with the
+// given indentation, formatted in the working time of the calendar if not nil.
+func printDurationStats(indent string, durations []time.Duration, cal *calendar.Calendar) {
+	if len(durations) == 0 {
+		return
+	}
+	slices.Sort(durations)
+	fmt.Println(indent+"- Min:", formatWorkingDuration(math.Min(durations), cal))
+	fmt.Println(indent+"- Max:", formatWorkingDuration(math.Max(durations), cal))
+	fmt.Println(indent+"- Mean:", formatWorkingDuration(math.Mean(durations), cal))
+	fmt.Println(indent+"- Median:", formatWorkingDuration(math.Median(durations), cal))
+	fmt.Println(indent+"- 90th percentile:", formatWorkingDuration(math.Percentile(durations, 0.9), cal))
+	fmt.Println(indent+"- 95th percentile:", formatWorkingDuration(math.Percentile(durations, 0.95), cal))
+	fmt.Println(indent+"- 99th percentile:", formatWorkingDuration(math.Percentile(durations, 0.99), cal))
+}
+
 func formatDuration(d time.Duration) string {
 	seconds := d / time.Second
 	minutes, seconds := seconds/60, seconds%60
@@ -4,14 +4,20 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/shizhMSFT/gha/pkg/analysis"
+	"github.com/shizhMSFT/gha/pkg/calendar"
+	"github.com/shizhMSFT/gha/pkg/container/set"
 	"github.com/shizhMSFT/gha/pkg/github"
+	"github.com/shizhMSFT/gha/pkg/identity"
 	"github.com/shizhMSFT/gha/pkg/markdown"
+	"github.com/shizhMSFT/gha/pkg/math"
 	"github.com/shizhMSFT/gha/pkg/sort"
 	"github.com/urfave/cli/v3"
+	"golang.org/x/exp/maps"
 )
 
 var pullRequestReviewCommand = &cli.Command{
@@ -19,7 +25,7 @@ var pullRequestReviewCommand = &cli.Command{
 	Usage:     "analyze pull request reviews",
 	ArgsUsage: "<review_snapshot> [...]",
 	Aliases:   []string{"pr", "p"},
-	Flags: []cli.Flag{
+	Flags: append([]cli.Flag{
 		&cli.IntFlag{
 			Name:     "ago",
 			Usage:    "only include snapshots that are at least `DAYS` old",
@@ -37,7 +43,27 @@ var pullRequestReviewCommand = &cli.Command{
 			Config:   cli.TimestampConfig{Layout: time.DateOnly},
 			OnlyOnce: true,
 		},
-	},
+		&cli.StringSliceFlag{
+			Name:  "issues",
+			Usage: "specify issue `SNAPSHOT` files in the same order as the review snapshots to analyze review latency",
+		},
+		&cli.IntFlag{
+			Name:     "sla",
+			Usage:    "report pull requests that have not received a review more than `DAYS`",
+			OnlyOnce: true,
+		},
+		calendarFlag(),
+		&cli.StringFlag{
+			Name:     "identities",
+			Usage:    "resolve accounts to people with an identity `FILE`",
+			OnlyOnce: true,
+		},
+		&cli.StringFlag{
+			Name:     "bucket",
+			Usage:    "report trends in time buckets of `{week, month, quarter}`",
+			OnlyOnce: true,
+		},
+	}, botFlags()...),
 	Action: runPullRequestReview,
 }
 
@@ -57,33 +83,285 @@ func runPullRequestReview(ctx *cli.Context) error {
 	if date := ctx.Value("end-date").(time.Time); !date.IsZero() {
 		timeFrame.End = date
 	}
+	issuePaths := ctx.StringSlice("issues")
+	if len(issuePaths) > 0 && len(issuePaths) != ctx.NArg() {
+		return fmt.Errorf("mismatched number of issue snapshots: %d issue snapshots for %d review snapshots", len(issuePaths), ctx.NArg())
+	}
+	slaDays := ctx.Int("sla")
+	bots, err := parseBotFilter(ctx)
+	if err != nil {
+		return err
+	}
+	if bots.only && len(issuePaths) == 0 {
+		return errors.New("--only-bots requires --issues to find pull requests authored by bots")
+	}
+	var identities *identity.Map
+	if path := ctx.String("identities"); path != "" {
+		identities, err = readIdentities(path)
+		if err != nil {
+			return err
+		}
+	}
+	cal, err := parseCalendar(ctx)
+	if err != nil {
+		return err
+	}
+	sla := days(slaDays, cal)
+	if ctx.String("bucket") != "" {
+		return runPullRequestReviewBuckets(ctx, timeFrame, issuePaths, bots, identities, cal)
+	}
 
 	// generate report
 	fmt.Println("Pull Request Review Count")
 	fmt.Println("=========================")
 	printTimeFrame(timeFrame)
+	printCalendar(cal)
 	report := analysis.NewPullRequestReviewReport(timeFrame)
-	for _, path := range ctx.Args().Slice() {
+	report.Identities = identities
+	report.Calendar = cal
+	report.Bots = bots.classifier
+	activity := newBotActivity(bots.classifier, timeFrame)
+	for i, path := range ctx.Args().Slice() {
 		fmt.Println()
 		fmt.Println("##", path)
-		snapshotJSON, err := os.ReadFile(path)
+		snapshot, err := readPullRequestReviews(path)
 		if err != nil {
 			return err
 		}
-		snapshot, err := github.ParsePullRequestReviews(snapshotJSON)
+		activity.addReviews(snapshot)
+		var issues map[int]github.Issue
+		if len(issuePaths) > 0 {
+			issues, err = readIssues(issuePaths[i])
+			if err != nil {
+				return err
+			}
+			activity.addIssues(analysis.Filter(issues, github.Issue.IsPullRequest))
+			issues = bots.issues(issues)
+		}
+		snapshot = filterActivities(bots, snapshot, issues, reviewer)
+		reviewSummary := report.Summarize(path, issues, snapshot)
+		printPullRequestReviewCount(reviewSummary.ReviewCount())
+		printPullRequestReviewStates(reviewSummary.States)
+		if issues == nil {
+			continue
+		}
+		summary := report.SummarizeFirstReviews(path, issues, snapshot)
+		printFirstReviewSummary(cal, summary)
+		if sla > 0 {
+			fmt.Println()
+			fmt.Println("#### Out of SLA:", slaDays, dayUnit(cal))
+			fmt.Println()
+			now := timeFrame.End
+			if now.IsZero() {
+				now = time.Now()
+			}
+			pullRequests := summary.OutOfSLA(sla, now, cal)
+			if len(pullRequests) > 0 {
+				table := markdown.NewTable("#PR", "Duration", "Title")
+				for _, pullRequest := range pullRequests {
+					table.AddRow(
+						fmt.Sprintf("#%d", pullRequest.Key),
+						formatWorkingDuration(pullRequest.Value, cal),
+						issues[pullRequest.Key].Title,
+					)
+				}
+				table.Print(os.Stdout)
+			} else {
+				fmt.Println("No pull requests out of SLA")
+			}
+		}
+		printMergeReviewSummary(cal, report.SummarizeMergeReviews(path, issues, snapshot))
+	}
+	if ctx.NArg() > 1 {
+		fmt.Println()
+		fmt.Println("## Overall")
+		printPullRequestReviewCount(report.ReviewCount())
+		printPullRequestReviewStates(report.StateCount())
+		if len(issuePaths) > 0 {
+			printFirstReviewSummary(cal, maps.Values(report.FirstReviews)...)
+			printMergeReviewSummary(cal, maps.Values(report.Merges)...)
+		}
+	}
+	if bots.exclude {
+		activity.print()
+	}
+	return nil
+}
+
+// reviewBucket is the review activity in a time bucket.
+type reviewBucket struct {
+	reviews      int
+	reviewers    set.Set[string]
+	pullRequests int // reviewed pull requests
+	opened       int // created pull requests
+	durations    []time.Duration
+	noReview     int
+}
+
+func (b *reviewBucket) add(reviewSummary *analysis.PullRequestReviewSummary, firstReviewSummary *analysis.FirstReviewSummary) {
+	if b.reviewers == nil {
+		b.reviewers = set.New[string]()
+	}
+	numbers := set.New[int]()
+	for reviewer, reviewed := range reviewSummary.Reviewers {
+		b.reviewers.Add(reviewer)
+		for number := range reviewed {
+			numbers.Add(number)
+		}
+	}
+	for _, states := range reviewSummary.States {
+		for _, count := range states {
+			b.reviews += count
+		}
+	}
+	b.pullRequests += numbers.Len()
+	if firstReviewSummary == nil {
+		return
+	}
+	for _, duration := range firstReviewSummary.Reviewed {
+		b.durations = append(b.durations, duration)
+	}
+	b.noReview += len(firstReviewSummary.NoReview) + len(firstReviewSummary.ClosedWithoutReview)
+	b.opened += len(firstReviewSummary.Reviewed) + len(firstReviewSummary.NoReview) + len(firstReviewSummary.ClosedWithoutReview)
+}
+
+func runPullRequestReviewBuckets(ctx *cli.Context, timeFrame analysis.TimeFrame, issuePaths []string, bots *botFilter, identities *identity.Map, cal *calendar.Calendar) error {
+	// read snapshots
+	snapshots := make([]map[int][]github.PullRequestReview, 0, ctx.NArg())
+	var first time.Time
+	for _, path := range ctx.Args().Slice() {
+		snapshot, err := readPullRequestReviews(path)
 		if err != nil {
 			return err
 		}
-		printPullRequestReviewCount(report.Summarize(path, snapshot).ReviewCount())
+		for _, reviews := range snapshot {
+			for _, review := range reviews {
+				if !review.SubmittedAt.IsZero() && (first.IsZero() || review.SubmittedAt.Before(first)) {
+					first = review.SubmittedAt
+				}
+			}
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	issueSnapshots := make([]map[int]github.Issue, 0, len(issuePaths))
+	for i, path := range issuePaths {
+		snapshot, err := readIssues(path)
+		if err != nil {
+			return err
+		}
+		snapshot = bots.issues(snapshot)
+		snapshots[i] = filterActivities(bots, snapshots[i], snapshot, reviewer)
+		issueSnapshots = append(issueSnapshots, snapshot)
+	}
+	if len(issuePaths) == 0 {
+		for i, snapshot := range snapshots {
+			snapshots[i] = filterActivities(bots, snapshot, nil, reviewer)
+		}
+	}
+	if created := firstCreated(issueSnapshots...); !created.IsZero() && created.Before(first) {
+		first = created
+	}
+	size, buckets, err := parseBuckets(ctx, timeFrame, first)
+	if err != nil {
+		return err
+	}
+
+	// generate report
+	fmt.Println("Pull Request Review Trends")
+	fmt.Println("==========================")
+	printTimeFrame(timeFrame)
+	printCalendar(cal)
+	fmt.Println("- Bucket:", size)
+	overall := make([]reviewBucket, len(buckets))
+	for i, path := range ctx.Args().Slice() {
+		fmt.Println()
+		fmt.Println("##", path)
+		var issues map[int]github.Issue
+		if len(issueSnapshots) > 0 {
+			issues = issueSnapshots[i]
+		}
+		rows := make([]reviewBucket, len(buckets))
+		for j, bucket := range buckets {
+			reviewSummary := analysis.SummarizePullRequestReviews(issues, snapshots[i], bucket, identities)
+			var firstReviewSummary *analysis.FirstReviewSummary
+			if issues != nil {
+				firstReviewSummary = analysis.SummarizeFirstReviews(analysis.SummarizeFirstReviewsOptions{
+					TimeFrame:  bucket,
+					Issues:     issues,
+					Reviews:    snapshots[i],
+					Calendar:   cal,
+					Bots:       bots.classifier,
+					Identities: identities,
+				})
+			}
+			rows[j].add(reviewSummary, firstReviewSummary)
+			overall[j].add(reviewSummary, firstReviewSummary)
+		}
+		printReviewBuckets(size, buckets, rows, len(issueSnapshots) > 0, cal)
 	}
 	if ctx.NArg() > 1 {
 		fmt.Println()
 		fmt.Println("## Overall")
-		printPullRequestReviewCount(report.ReviewCount())
+		printReviewBuckets(size, buckets, overall, len(issueSnapshots) > 0, cal)
 	}
 	return nil
 }
 
+func printReviewBuckets(size analysis.BucketSize, buckets []analysis.TimeFrame, rows []reviewBucket, withIssues bool, cal *calendar.Calendar) {
+	header := []string{"Bucket", "Reviews", "Reviewers", "PRs Reviewed"}
+	if withIssues {
+		header = append(header, "PRs Opened", "Median First Review", "P90 First Review", "Never Reviewed")
+	}
+	table := newTrendTable(header...)
+	for i, row := range rows {
+		cells := []trendCell{
+			countCell(row.reviews),
+			countCell(row.reviewers.Len()),
+			countCell(row.pullRequests),
+		}
+		if withIssues {
+			cells = append(cells,
+				countCell(row.opened),
+				medianCell(row.durations, cal),
+				percentileCell(row.durations, 0.9, cal),
+				countCell(row.noReview),
+			)
+		}
+		table.AddBucket(size.Label(buckets[i].Start), cells...)
+	}
+	table.Print()
+}
+
+func readPullRequestReviews(path string) (map[int][]github.PullRequestReview, error) {
+	snapshotJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return github.ParsePullRequestReviews(snapshotJSON)
+}
+
+func printFirstReviewSummary(cal *calendar.Calendar, summaries ...*analysis.FirstReviewSummary) {
+	var durations []time.Duration
+	var noReview, closedWithoutReview int
+	for _, summary := range summaries {
+		for _, duration := range summary.Reviewed {
+			durations = append(durations, duration)
+		}
+		noReview += len(summary.NoReview)
+		closedWithoutReview += len(summary.ClosedWithoutReview)
+	}
+
+	fmt.Println()
+	fmt.Println("### First Review Time")
+	fmt.Println()
+	fmt.Println("- Pull requests:", len(durations)+noReview+closedWithoutReview)
+	fmt.Println("  - Reviewed:", len(durations))
+	printDurationStats("    ", durations, cal)
+	fmt.Println("  - Never reviewed:", noReview+closedWithoutReview)
+	fmt.Println("    - Open:", noReview)
+	fmt.Println("    - Closed:", closedWithoutReview)
+}
+
 func printPullRequestReviewCount(reviewCounts map[string]int) {
 	// sort by review counts
 	counts := sort.SliceFromMap(reviewCounts).Sort(func(a, b sort.MapEntry[string, int]) int {
@@ -109,3 +387,64 @@ func printPullRequestReviewCount(reviewCounts map[string]int) {
 	fmt.Println()
 	table.Print(os.Stdout)
 }
+
+func printPullRequestReviewStates(stateCounts map[string]map[string]int) {
+	if len(stateCounts) == 0 {
+		return
+	}
+
+	// sort by total review counts
+	totals := make(map[string]int)
+	for reviewer, states := range stateCounts {
+		for _, count := range states {
+			totals[reviewer] += count
+		}
+	}
+	counts := sort.SliceFromMap(totals).Sort(func(a, b sort.MapEntry[string, int]) int {
+		return b.Value - a.Value
+	})
+
+	// print table
+	fmt.Println()
+	fmt.Println("### Review Outcomes")
+	table := markdown.NewTable("Reviewer", "Reviews", "Approved", "Changes Requested", "Commented", "Dismissed")
+	for _, entry := range counts {
+		states := stateCounts[entry.Key]
+		table.AddRow(entry.Key, entry.Value,
+			states[github.ReviewStateApproved],
+			states[github.ReviewStateChangesRequested],
+			states[github.ReviewStateCommented],
+			states[github.ReviewStateDismissed],
+		)
+	}
+	fmt.Println()
+	table.Print(os.Stdout)
+}
+
+func printMergeReviewSummary(cal *calendar.Calendar, summaries ...*analysis.MergeReviewSummary) {
+	var rounds []float64
+	var durations []time.Duration
+	for _, summary := range summaries {
+		for _, round := range summary.Rounds {
+			rounds = append(rounds, float64(round))
+		}
+		for _, duration := range summary.ApprovalToMerge {
+			durations = append(durations, duration)
+		}
+	}
+
+	fmt.Println()
+	fmt.Println("### Merged Pull Requests")
+	fmt.Println()
+	fmt.Println("- Reviewed before merge:", len(rounds))
+	if len(rounds) > 0 {
+		slices.Sort(rounds)
+		fmt.Printf("  - Review rounds: mean %.2f, median %.1f, max %.0f\n", math.Mean(rounds), math.Median(rounds), math.Max(rounds))
+		fmt.Println("    - Change requests later dismissed are not counted as rounds.")
+	}
+	fmt.Println("- Approved before merge:", len(durations))
+	if len(durations) > 0 {
+		fmt.Println("  - Approval to merge:")
+		printDurationStats("    ", durations, cal)
+	}
+}
@@ -34,6 +34,11 @@ var snapshotCommand = &cli.Command{
 			Config:   cli.TimestampConfig{Layout: time.DateOnly},
 			OnlyOnce: true,
 		},
+		&cli.BoolFlag{
+			Name:     "milestones",
+			Usage:    "include milestones in the snapshot",
+			OnlyOnce: true,
+		},
 		&cli.BoolFlag{
 			Name:     "pr-reviews",
 			Usage:    "include pull request reviews in the snapshot",
@@ -50,6 +55,38 @@ var snapshotCommand = &cli.Command{
 			Config:   cli.TimestampConfig{Layout: time.DateOnly},
 			OnlyOnce: true,
 		},
+		&cli.BoolFlag{
+			Name:     "pr-review-comments",
+			Usage:    "include review comments of pull requests in the snapshot",
+			OnlyOnce: true,
+		},
+		&cli.IntFlag{
+			Name:     "pr-review-comments-ago",
+			Usage:    "include review comments of pull requests since `DAYS` ago",
+			OnlyOnce: true,
+		},
+		&cli.TimestampFlag{
+			Name:     "pr-review-comments-since",
+			Usage:    "include review comments of pull requests since `DATE`",
+			Config:   cli.TimestampConfig{Layout: time.DateOnly},
+			OnlyOnce: true,
+		},
+		&cli.BoolFlag{
+			Name:     "pr-files",
+			Usage:    "include changed files of pull requests in the snapshot",
+			OnlyOnce: true,
+		},
+		&cli.IntFlag{
+			Name:     "pr-files-ago",
+			Usage:    "include changed files of pull requests since `DAYS` ago",
+			OnlyOnce: true,
+		},
+		&cli.TimestampFlag{
+			Name:     "pr-files-since",
+			Usage:    "include changed files of pull requests since `DATE`",
+			Config:   cli.TimestampConfig{Layout: time.DateOnly},
+			OnlyOnce: true,
+		},
 		&cli.BoolFlag{
 			Name:     "issue-comments",
 			Usage:    "include issue comments in the snapshot",
@@ -66,6 +103,22 @@ var snapshotCommand = &cli.Command{
 			Config:   cli.TimestampConfig{Layout: time.DateOnly},
 			OnlyOnce: true,
 		},
+		&cli.BoolFlag{
+			Name:     "timelines",
+			Usage:    "include timeline events of issues and pull requests in the snapshot",
+			OnlyOnce: true,
+		},
+		&cli.IntFlag{
+			Name:     "timelines-ago",
+			Usage:    "include timeline events since `DAYS` ago",
+			OnlyOnce: true,
+		},
+		&cli.TimestampFlag{
+			Name:     "timelines-since",
+			Usage:    "include timeline events since `DATE`",
+			Config:   cli.TimestampConfig{Layout: time.DateOnly},
+			OnlyOnce: true,
+		},
 	},
 	Action: runSnapshot,
 }
@@ -108,16 +161,53 @@ func runSnapshot(ctx *cli.Context) error {
 	}
 	fmt.Println("Saved snapshot to", path)
 
+	if ctx.Bool("milestones") {
+		if err := snapshotMilestones(ctx, org, repo, client); err != nil {
+			return err
+		}
+	}
 	if ctx.Bool("pr-reviews") {
 		if err := snapshotPullRequestReviews(ctx, org, repo, client, snapshot); err != nil {
 			return err
 		}
 	}
+	if ctx.Bool("pr-review-comments") {
+		if err := snapshotPullRequestReviewComments(ctx, org, repo, client, snapshot); err != nil {
+			return err
+		}
+	}
+	if ctx.Bool("pr-files") {
+		if err := snapshotPullRequestFiles(ctx, org, repo, client, snapshot); err != nil {
+			return err
+		}
+	}
 	if ctx.Bool("issue-comments") {
 		if err := snapshotIssueComments(ctx, org, repo, client, snapshot); err != nil {
 			return err
 		}
 	}
+	if ctx.Bool("timelines") {
+		if err := snapshotTimelines(ctx, org, repo, client, snapshot); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func snapshotMilestones(ctx *cli.Context, org string, repo string, client *github.Client) error {
+	fmt.Println("Fetching milestones...")
+	milestones, err := client.Milestones(ctx.Context, org, repo)
+	if err != nil {
+		return err
+	}
+
+	// save milestones
+	path := fmt.Sprintf("%s_%s_%s_milestones.json", org, repo, time.Now().UTC().Format("20060102_150405"))
+	if err := os.WriteFile(path, milestones, 0644); err != nil {
+		return err
+	}
+	fmt.Println("Saved milestones to", path)
 
 	return nil
 }
@@ -182,6 +272,126 @@ func snapshotPullRequestReviews(ctx *cli.Context, org string, repo string, clien
 	return nil
 }
 
+func snapshotPullRequestReviewComments(ctx *cli.Context, org string, repo string, client *github.Client, snapshot []byte) error {
+	// parse flags
+	var start time.Time
+	if ago := ctx.Int("pr-review-comments-ago"); ago > 0 {
+		start = time.Now().UTC().AddDate(0, 0, int(-ago))
+	}
+	if date := ctx.Value("pr-review-comments-since").(time.Time); !date.IsZero() {
+		start = date
+	}
+
+	// fetch pull request review comments
+	issues, err := github.ParseIssues(snapshot)
+	if err != nil {
+		return err
+	}
+	comments := make(map[int]json.RawMessage)
+	for _, issue := range issues {
+		if issue.IsPullRequest() {
+			if !start.IsZero() && issue.CreatedAt.Before(start) {
+				continue
+			}
+			comments[issue.Number] = nil
+		}
+	}
+	total := len(comments)
+	fmt.Printf("Fetching review comments of %d pull requests", total)
+	if !start.IsZero() {
+		fmt.Printf(" since %s", start.Format(time.DateOnly))
+	}
+	fmt.Println("...")
+
+	count := 0
+	for number := range comments {
+		comment, err := client.PullRequestReviewComments(ctx.Context, org, repo, number)
+		if err != nil {
+			return err
+		}
+		comments[number] = comment
+		count++
+		fmt.Printf(".")
+		if count%50 == 0 {
+			fmt.Printf(" %6g%%\n", float64(10000*count/total)/100.0)
+		}
+	}
+	fmt.Println(strings.Repeat(" ", 50-count%50), "100.00%")
+
+	// save review comments
+	path := fmt.Sprintf("%s_%s_%s_review_comments.json", org, repo, time.Now().UTC().Format("20060102_150405"))
+	commentsJSON, err := json.Marshal(comments)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, commentsJSON, 0644); err != nil {
+		return err
+	}
+	fmt.Println("Saved pull request review comments to", path)
+
+	return nil
+}
+
+func snapshotPullRequestFiles(ctx *cli.Context, org string, repo string, client *github.Client, snapshot []byte) error {
+	// parse flags
+	var start time.Time
+	if ago := ctx.Int("pr-files-ago"); ago > 0 {
+		start = time.Now().UTC().AddDate(0, 0, int(-ago))
+	}
+	if date := ctx.Value("pr-files-since").(time.Time); !date.IsZero() {
+		start = date
+	}
+
+	// fetch pull request files
+	issues, err := github.ParseIssues(snapshot)
+	if err != nil {
+		return err
+	}
+	files := make(map[int]json.RawMessage)
+	for _, issue := range issues {
+		if issue.IsPullRequest() {
+			if !start.IsZero() && issue.CreatedAt.Before(start) {
+				continue
+			}
+			files[issue.Number] = nil
+		}
+	}
+	total := len(files)
+	fmt.Printf("Fetching changed files of %d pull requests", total)
+	if !start.IsZero() {
+		fmt.Printf(" since %s", start.Format(time.DateOnly))
+	}
+	fmt.Println("...")
+
+	count := 0
+	for number := range files {
+		file, err := client.PullRequestFiles(ctx.Context, org, repo, number)
+		if err != nil {
+			return err
+		}
+		files[number] = file
+		count++
+		fmt.Printf(".")
+		if count%50 == 0 {
+			fmt.Printf(" %6g%%\n", float64(10000*count/total)/100.0)
+		}
+	}
+	fmt.Println(strings.Repeat(" ", 50-count%50), "100.00%")
+
+	// save files
+	path := fmt.Sprintf("%s_%s_%s_files.json", org, repo, time.Now().UTC().Format("20060102_150405"))
+	filesJSON, err := json.Marshal(files)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, filesJSON, 0644); err != nil {
+		return err
+	}
+	fmt.Println("Saved pull request files to", path)
+
+	return nil
+}
+
 func snapshotIssueComments(ctx *cli.Context, org string, repo string, client *github.Client, snapshot []byte) error {
 	// parse flags
 	var start time.Time
@@ -239,3 +449,61 @@ func snapshotIssueComments(ctx *cli.Context, org string, repo string, client *gi
 
 	return nil
 }
+
+func snapshotTimelines(ctx *cli.Context, org string, repo string, client *github.Client, snapshot []byte) error {
+	// parse flags
+	var start time.Time
+	if ago := ctx.Int("timelines-ago"); ago > 0 {
+		start = time.Now().UTC().AddDate(0, 0, int(-ago))
+	}
+	if date := ctx.Value("timelines-since").(time.Time); !date.IsZero() {
+		start = date
+	}
+
+	// fetch timelines
+	issues, err := github.ParseIssues(snapshot)
+	if err != nil {
+		return err
+	}
+	timelines := make(map[int]json.RawMessage)
+	for _, issue := range issues {
+		if !start.IsZero() && issue.CreatedAt.Before(start) {
+			continue
+		}
+		timelines[issue.Number] = nil
+	}
+	total := len(timelines)
+	fmt.Printf("Fetching timelines of %d issues and pull requests", total)
+	if !start.IsZero() {
+		fmt.Printf(" since %s", start.Format(time.DateOnly))
+	}
+	fmt.Println("...")
+
+	count := 0
+	for number := range timelines {
+		timeline, err := client.IssueTimeline(ctx.Context, org, repo, number)
+		if err != nil {
+			return err
+		}
+		timelines[number] = timeline
+		count++
+		fmt.Printf(".")
+		if count%50 == 0 {
+			fmt.Printf(" %6g%%\n", float64(10000*count/total)/100.0)
+		}
+	}
+	fmt.Println(strings.Repeat(" ", 50-count%50), "100.00%")
+
+	// save timelines
+	path := fmt.Sprintf("%s_%s_%s_timelines.json", org, repo, time.Now().UTC().Format("20060102_150405"))
+	timelinesJSON, err := json.Marshal(timelines)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, timelinesJSON, 0644); err != nil {
+		return err
+	}
+	fmt.Println("Saved timelines to", path)
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shizhMSFT/gha/pkg/analysis"
+	"github.com/shizhMSFT/gha/pkg/github"
+	"github.com/shizhMSFT/gha/pkg/markdown"
+	"github.com/urfave/cli/v3"
+)
+
+var staleCommand = &cli.Command{
+	Name:      "stale",
+	Usage:     "list open issues and pull requests without recent activity",
+	ArgsUsage: "<issue_snapshot> [<issue_comment_snapshot>]",
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:     "days",
+			Usage:    "list items without human activity for more than `DAYS`",
+			Value:    30,
+			OnlyOnce: true,
+		},
+		&cli.StringFlag{
+			Name:     "type",
+			Usage:    "only list `{issue, pr, all}`",
+			Value:    "all",
+			OnlyOnce: true,
+		},
+		&cli.StringSliceFlag{
+			Name:  "label",
+			Usage: "only list items with any of the `LABEL`s",
+		},
+		&cli.StringSliceFlag{
+			Name:  "exclude-label",
+			Usage: "exclude items with any of the `LABEL`s",
+		},
+		&cli.StringFlag{
+			Name:     "author-type",
+			Usage:    "only list items authored by `{user, bot, all}`",
+			Value:    "all",
+			OnlyOnce: true,
+		},
+		botPatternFlag(),
+	},
+	Action: runStale,
+}
+
+func runStale(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return errors.New("no issue snapshot file specified")
+	}
+
+	// parse flags
+	days := ctx.Int("days")
+	if days < 0 {
+		return errors.New("days must not be negative")
+	}
+	kind := strings.ToLower(ctx.String("type"))
+	switch kind {
+	case "issue", "pr", "all":
+	default:
+		return fmt.Errorf("invalid type: %s", kind)
+	}
+	authorType := strings.ToLower(ctx.String("author-type"))
+	switch authorType {
+	case "user", "bot", "all":
+	default:
+		return fmt.Errorf("invalid author type: %s", authorType)
+	}
+	include := ctx.StringSlice("label")
+	exclude := ctx.StringSlice("exclude-label")
+	bots, err := parseBotClassifier(ctx)
+	if err != nil {
+		return err
+	}
+
+	// read snapshots
+	issues, err := readIssues(ctx.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	var comments map[int][]github.IssueComment
+	if path := ctx.Args().Get(1); path != "" {
+		comments, err = readIssueComments(path)
+		if err != nil {
+			return err
+		}
+	}
+	issues = analysis.Filter(issues, func(issue github.Issue) bool {
+		switch {
+		case kind == "issue" && issue.IsPullRequest(),
+			kind == "pr" && !issue.IsPullRequest(),
+			authorType == "user" && bots.IsBot(issue.User),
+			authorType == "bot" && !bots.IsBot(issue.User):
+			return false
+		}
+		if len(include) > 0 && !hasAnyLabel(issue, include) {
+			return false
+		}
+		return !hasAnyLabel(issue, exclude)
+	})
+
+	// generate report
+	now := time.Now().UTC()
+	items := analysis.FindStale(issues, comments, time.Duration(days)*24*time.Hour, now, bots)
+	fmt.Println("Stale Report")
+	fmt.Println("============")
+	fmt.Println()
+	fmt.Println("- No activity for more than", days, "days")
+	if comments == nil {
+		fmt.Println("- Last activity: last update, including bots")
+	} else {
+		fmt.Println("- Last activity: last human comment, or last update for pull requests")
+	}
+	fmt.Println("- Stale:", len(items))
+	fmt.Println()
+	if len(items) == 0 {
+		fmt.Println("No stale items")
+		return nil
+	}
+	table := markdown.NewTable("#Issue", "Type", "Idle", "Last Activity", "Comments", "Author", "Title")
+	for _, item := range items {
+		issue := item.Issue
+		itemType := "issue"
+		if issue.IsPullRequest() {
+			itemType = "pr"
+		}
+		table.AddRow(
+			fmt.Sprintf("[#%d](%s)", issue.Number, issue.HTMLURL),
+			itemType,
+			formatDuration(item.Idle),
+			item.LastActivity.Format(time.DateOnly),
+			issue.Comments,
+			issue.User.Login,
+			issue.Title,
+		)
+	}
+	table.Print(os.Stdout)
+	return nil
+}
+
+// hasAnyLabel reports whether the issue has any of the labels, matched
+// case-insensitively.
+func hasAnyLabel(issue github.Issue, labels []string) bool {
+	return slices.ContainsFunc(issue.Labels, func(label github.Label) bool {
+		return slices.ContainsFunc(labels, func(name string) bool {
+			return strings.EqualFold(label.Name, name)
+		})
+	})
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/shizhMSFT/gha/pkg/analysis"
+	"github.com/shizhMSFT/gha/pkg/container/set"
+	"github.com/shizhMSFT/gha/pkg/github"
+	"github.com/shizhMSFT/gha/pkg/markdown"
+	"github.com/urfave/cli/v3"
+)
+
+var suggestReviewersCommand = &cli.Command{
+	Name:      "suggest-reviewers",
+	Usage:     "suggest reviewers for a pull request or an author based on review history",
+	ArgsUsage: "<issue_snapshot> <review_snapshot> [<pr_files_snapshot>]",
+	Aliases:   []string{"sr"},
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:     "pr",
+			Usage:    "suggest reviewers for the pull request `NUMBER`",
+			OnlyOnce: true,
+		},
+		&cli.StringFlag{
+			Name:     "author",
+			Usage:    "suggest reviewers for pull requests by the author `LOGIN`",
+			OnlyOnce: true,
+		},
+		&cli.StringFlag{
+			Name:     "maintainers",
+			Usage:    "only suggest accounts in a CODEOWNERS, OWNERS or MAINTAINERS file, including those without reviews",
+			OnlyOnce: true,
+		},
+		&cli.StringFlag{
+			Name:     "identities",
+			Usage:    "resolve accounts to people with an identity `FILE`",
+			OnlyOnce: true,
+		},
+		&cli.IntFlag{
+			Name:     "top",
+			Usage:    "suggest at most `N` reviewers",
+			Value:    5,
+			OnlyOnce: true,
+		},
+		botPatternFlag(),
+	},
+	Action: runSuggestReviewers,
+}
+
+func runSuggestReviewers(ctx *cli.Context) error {
+	if ctx.NArg() < 2 {
+		return errors.New("no issue or review snapshot files specified")
+	}
+
+	// parse flags
+	opts := analysis.SuggestReviewersOptions{
+		PullRequest: int(ctx.Int("pr")),
+		Author:      ctx.String("author"),
+	}
+	if (opts.PullRequest == 0) == (opts.Author == "") {
+		return errors.New("exactly one of --pr and --author must be specified")
+	}
+	if path := ctx.String("maintainers"); path != "" {
+		maintainers, err := readMaintainers(path)
+		if err != nil {
+			return err
+		}
+		opts.Candidates = set.New(maintainers...)
+	}
+	var err error
+	if path := ctx.String("identities"); path != "" {
+		opts.Identities, err = readIdentities(path)
+		if err != nil {
+			return err
+		}
+	}
+	opts.Bots, err = parseBotClassifier(ctx)
+	if err != nil {
+		return err
+	}
+	top := int(ctx.Int("top"))
+
+	// read snapshots
+	opts.Issues, err = readIssues(ctx.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	opts.Reviews, err = readPullRequestReviews(ctx.Args().Get(1))
+	if err != nil {
+		return err
+	}
+	if path := ctx.Args().Get(2); path != "" {
+		opts.Files, err = readPullRequestFiles(path)
+		if err != nil {
+			return err
+		}
+	}
+	if opts.PullRequest != 0 {
+		pullRequest, ok := opts.Issues[opts.PullRequest]
+		if !ok || !pullRequest.IsPullRequest() {
+			return fmt.Errorf("pull request #%d not found", opts.PullRequest)
+		}
+	}
+
+	// generate report
+	fmt.Println("Reviewer Suggestions")
+	fmt.Println("====================")
+	fmt.Println()
+	if opts.PullRequest != 0 {
+		pullRequest := opts.Issues[opts.PullRequest]
+		fmt.Printf("- Pull Request: #%d %s\n", pullRequest.Number, pullRequest.Title)
+		fmt.Println("- Author:", pullRequest.User.Login)
+		if opts.Files != nil {
+			fmt.Println("- Changed Files:", len(opts.Files[opts.PullRequest]))
+		}
+	} else {
+		fmt.Println("- Author:", opts.Author)
+	}
+	fmt.Printf("- Score: %.1f × interaction + %.1f × familiarity + %.1f × recency (normalized) - %.1f × load\n",
+		analysis.InteractionWeight, analysis.FamiliarityWeight, analysis.RecencyWeight, analysis.LoadWeight)
+
+	suggestions := analysis.SuggestReviewers(opts)
+	fmt.Println()
+	if len(suggestions) == 0 {
+		fmt.Println("No reviewer candidates")
+		return nil
+	}
+	if top > 0 && len(suggestions) > top {
+		suggestions = suggestions[:top]
+	}
+	table := markdown.NewTable("Rank", "Reviewer", "Score", "Interaction", "Familiarity", "Recency", "Load", "Explanation")
+	for i, suggestion := range suggestions {
+		familiarity := "-"
+		if suggestion.TotalFiles > 0 {
+			familiarity = fmt.Sprintf("%.2f", suggestion.Familiarity)
+		}
+		table.AddRow(i+1, suggestion.Reviewer,
+			fmt.Sprintf("%.2f", suggestion.Score),
+			fmt.Sprintf("%.2f", suggestion.Interaction),
+			familiarity,
+			fmt.Sprintf("%.2f", suggestion.Recency),
+			fmt.Sprintf("%.2f", suggestion.Load),
+			explainSuggestion(suggestion),
+		)
+	}
+	table.Print(os.Stdout)
+	return nil
+}
+
+func readPullRequestFiles(path string) (map[int][]github.PullRequestFile, error) {
+	snapshotJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return github.ParsePullRequestFiles(snapshotJSON)
+}
+
+func explainSuggestion(suggestion analysis.ReviewerSuggestion) string {
+	reasons := []string{
+		fmt.Sprintf("reviewed %d/%d PRs of the author", suggestion.Reviewed, suggestion.AuthorTotal),
+	}
+	if suggestion.TotalFiles > 0 {
+		reasons = append(reasons, fmt.Sprintf("reviewed the directories of %d/%d changed files before", suggestion.FamiliarFiles, suggestion.TotalFiles))
+	}
+	if suggestion.LastReview.IsZero() {
+		reasons = append(reasons, "no reviews yet")
+	} else {
+		reasons = append(reasons, fmt.Sprintf("last review %s ago", formatDuration(time.Since(suggestion.LastReview))))
+	}
+	reasons = append(reasons, fmt.Sprintf("reviewing %d open PRs", suggestion.OpenReviews))
+	return strings.Join(reasons, "; ")
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/shizhMSFT/gha/pkg/analysis"
+	"github.com/shizhMSFT/gha/pkg/calendar"
+	"github.com/shizhMSFT/gha/pkg/container/set"
+	"github.com/shizhMSFT/gha/pkg/github"
+	"github.com/shizhMSFT/gha/pkg/markdown"
+	"github.com/shizhMSFT/gha/pkg/math"
+	"github.com/urfave/cli/v3"
+	"golang.org/x/exp/maps"
+)
+
+var triageCommand = &cli.Command{
+	Name:      "triage",
+	Usage:     "analyze triage latency of issues",
+	ArgsUsage: "<issue_snapshot> <timeline_snapshot> [<issue_snapshot> <timeline_snapshot> ...]",
+	Aliases:   []string{"t"},
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:     "ago",
+			Usage:    "only include issues that are at least `DAYS` old",
+			OnlyOnce: true,
+		},
+		&cli.TimestampFlag{
+			Name:     "start-date",
+			Usage:    "only include issues that were created after `DATE`",
+			Config:   cli.TimestampConfig{Layout: time.DateOnly},
+			OnlyOnce: true,
+		},
+		&cli.TimestampFlag{
+			Name:     "end-date",
+			Usage:    "only include issues that were created before `DATE`",
+			Config:   cli.TimestampConfig{Layout: time.DateOnly},
+			OnlyOnce: true,
+		},
+		&cli.IntFlag{
+			Name:     "sla",
+			Usage:    "report issues that have not been triaged for more than `DAYS`",
+			OnlyOnce: true,
+		},
+		calendarFlag(),
+		&cli.StringFlag{
+			Name:     "maintainers",
+			Usage:    "only count triage by maintainers listed in a CODEOWNERS, OWNERS or MAINTAINERS file",
+			OnlyOnce: true,
+		},
+		botPatternFlag(),
+	},
+	Action: runTriage,
+}
+
+func runTriage(ctx *cli.Context) error {
+	if ctx.NArg() < 2 {
+		return errors.New("no issue or timeline snapshot files specified")
+	}
+	if ctx.NArg()%2 != 0 {
+		return fmt.Errorf("mismatched number of snapshots: %d snapshots are not pairs of issue and timeline snapshots", ctx.NArg())
+	}
+
+	// parse flags
+	var opts analysis.SummarizeTriageOptions
+	if ago := ctx.Int("ago"); ago > 0 {
+		opts.TimeFrame.Start = time.Now().UTC().AddDate(0, 0, int(-ago))
+	}
+	if date := ctx.Value("start-date").(time.Time); !date.IsZero() {
+		opts.TimeFrame.Start = date
+	}
+	if date := ctx.Value("end-date").(time.Time); !date.IsZero() {
+		opts.TimeFrame.End = date
+	}
+	if path := ctx.String("maintainers"); path != "" {
+		maintainers, err := readMaintainers(path)
+		if err != nil {
+			return err
+		}
+		opts.Maintainers = set.New(maintainers...)
+	}
+	slaDays := ctx.Int("sla")
+	var err error
+	opts.Calendar, err = parseCalendar(ctx)
+	if err != nil {
+		return err
+	}
+	sla := days(slaDays, opts.Calendar)
+	opts.Bots, err = parseBotClassifier(ctx)
+	if err != nil {
+		return err
+	}
+
+	// generate report
+	fmt.Println("Triage Summary")
+	fmt.Println("==============")
+	printTimeFrame(opts.TimeFrame)
+	printCalendar(opts.Calendar)
+	overall := analysis.NewTriageSummary()
+	overall.TimeFrame = opts.TimeFrame
+	args := ctx.Args().Slice()
+	for i := 0; i < len(args); i += 2 {
+		snapshotOpts := opts
+		snapshotOpts.Issues, err = readIssues(args[i])
+		if err != nil {
+			return err
+		}
+		snapshotOpts.Timelines, err = readTimelines(args[i+1])
+		if err != nil {
+			return err
+		}
+		summary := analysis.SummarizeTriage(snapshotOpts)
+		overall.Union(summary)
+		fmt.Println()
+		fmt.Println("##", args[i])
+		printTriageSummary(summary, opts.Calendar)
+		if sla > 0 {
+			printUntriagedOutOfSLA(summary, sla, slaDays, opts.Calendar)
+		}
+	}
+	if len(args) > 2 {
+		fmt.Println()
+		fmt.Println("## Overall")
+		printTriageSummary(overall, opts.Calendar)
+	}
+	return nil
+}
+
+func printUntriagedOutOfSLA(summary *analysis.TriageSummary, sla time.Duration, slaDays int64, cal *calendar.Calendar) {
+	fmt.Println()
+	fmt.Println("#### Untriaged Out of SLA:", slaDays, dayUnit(cal))
+	fmt.Println()
+	now := summary.TimeFrame.End
+	if now.IsZero() {
+		now = time.Now()
+	}
+	issues := make(map[int]github.Issue, len(summary.Untriaged))
+	for _, issue := range summary.Untriaged {
+		issues[issue.Number] = issue
+	}
+	entries := summary.OutOfSLA(sla, now, cal)
+	if len(entries) == 0 {
+		fmt.Println("No issues out of SLA")
+		return
+	}
+	table := markdown.NewTable("#Issue", "Duration", "Title")
+	for _, entry := range entries {
+		issue := issues[entry.Key]
+		table.AddRow(
+			fmt.Sprintf("[#%d](%s)", entry.Key, issue.HTMLURL),
+			formatWorkingDuration(entry.Value, cal),
+			issue.Title,
+		)
+	}
+	table.Print(os.Stdout)
+}
+
+func readTimelines(path string) (map[int][]github.TimelineEvent, error) {
+	snapshotJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return github.ParseTimelines(snapshotJSON)
+}
+
+func printTriageSummary(summary *analysis.TriageSummary, cal *calendar.Calendar) {
+	fmt.Println()
+	fmt.Println("### Time to Triage")
+	fmt.Println()
+	fmt.Println("- Issues:", len(summary.Triaged)+len(summary.Untriaged)+len(summary.ClosedUntriaged))
+	fmt.Println("  - Triaged:", len(summary.Triaged))
+	printDurationStats("    ", summary.Triaged, cal)
+	fmt.Println("  - Untriaged:", len(summary.Untriaged))
+	fmt.Println("  - Closed without triage:", len(summary.ClosedUntriaged))
+
+	fmt.Println()
+	fmt.Println("#### By Action")
+	fmt.Println()
+	table := markdown.NewTable("Action", "Issues", "Median", "P90", "P95")
+	for _, action := range analysis.TriageActions {
+		addTriageRow(table, "First "+action, summary.Actions[action], cal)
+	}
+	table.Print(os.Stdout)
+
+	if len(summary.Triagers) > 0 {
+		fmt.Println()
+		fmt.Println("#### By Triager")
+		fmt.Println()
+		triagers := maps.Keys(summary.Triagers)
+		slices.SortFunc(triagers, func(a, b string) int {
+			if c := cmp.Compare(len(summary.Triagers[b]), len(summary.Triagers[a])); c != 0 {
+				return c
+			}
+			return cmp.Compare(a, b)
+		})
+		table := markdown.NewTable("Triager", "Issues", "Median", "P90", "P95")
+		for _, triager := range triagers {
+			addTriageRow(table, "@"+triager, summary.Triagers[triager], cal)
+		}
+		table.Print(os.Stdout)
+	}
+}
+
+func addTriageRow(table *markdown.Table, name string, durations []time.Duration, cal *calendar.Calendar) {
+	if len(durations) == 0 {
+		table.AddRow(name, 0, "-", "-", "-")
+		return
+	}
+	slices.Sort(durations)
+	table.AddRow(name, len(durations),
+		formatWorkingDuration(math.Median(durations), cal),
+		formatWorkingDuration(math.Percentile(durations, 0.9), cal),
+		formatWorkingDuration(math.Percentile(durations, 0.95), cal),
+	)
+}
@@ -5,6 +5,7 @@ go 1.21
 require (
 	github.com/urfave/cli/v3 v3.0.0-alpha4
 	golang.org/x/exp v0.0.0-20230713183714-613f0c0eb8a1
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -0,0 +1,64 @@
+package actor
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/shizhMSFT/gha/pkg/github"
+)
+
+// Kind is the kind of an actor.
+type Kind string
+
+// Supported kinds of actors.
+const (
+	KindUser Kind = "user"
+	KindBot  Kind = "bot"
+)
+
+// Classifier classifies GitHub accounts into users and bots.
+// Accounts of the Bot type or with the `[bot]` suffix are always bots, and
+// accounts matching any of the patterns are bots as well, such as machine
+// users acting on behalf of CI systems.
+type Classifier struct {
+	patterns []*regexp.Regexp
+}
+
+// NewClassifier returns a classifier with additional regular expression
+// patterns matching the logins of bots.
+func NewClassifier(patterns ...string) (*Classifier, error) {
+	c := &Classifier{}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bot pattern %q: %w", pattern, err)
+		}
+		c.patterns = append(c.patterns, re)
+	}
+	return c, nil
+}
+
+// Classify returns the kind of the account.
+func (c *Classifier) Classify(account github.Account) Kind {
+	if c.IsBot(account) {
+		return KindBot
+	}
+	return KindUser
+}
+
+// IsBot reports whether the account is a bot.
+// A nil classifier only recognizes GitHub App bots.
+func (c *Classifier) IsBot(account github.Account) bool {
+	if account.IsBot() {
+		return true
+	}
+	if c == nil {
+		return false
+	}
+	for _, re := range c.patterns {
+		if re.MatchString(account.Login) {
+			return true
+		}
+	}
+	return false
+}
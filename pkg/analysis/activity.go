@@ -0,0 +1,213 @@
+package analysis
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shizhMSFT/gha/pkg/actor"
+	"github.com/shizhMSFT/gha/pkg/container/set"
+	"github.com/shizhMSFT/gha/pkg/github"
+)
+
+// Heatmap counts events by day of week and hour of day, where days start on
+// Monday.
+type Heatmap [7][24]int
+
+// Add counts an event at t in the location of t.
+func (h *Heatmap) Add(t time.Time) {
+	day := (int(t.Weekday()) + 6) % 7 // days since Monday
+	h[day][t.Hour()]++
+}
+
+// Union adds the counts of other.
+func (h *Heatmap) Union(other *Heatmap) {
+	for day := range h {
+		for hour := range h[day] {
+			h[day][hour] += other[day][hour]
+		}
+	}
+}
+
+// Total returns the total number of events.
+func (h *Heatmap) Total() int {
+	total := 0
+	for day := range h {
+		for _, count := range h[day] {
+			total += count
+		}
+	}
+	return total
+}
+
+// Max returns the largest count of an hour.
+func (h *Heatmap) Max() int {
+	max := 0
+	for day := range h {
+		for _, count := range h[day] {
+			if count > max {
+				max = count
+			}
+		}
+	}
+	return max
+}
+
+// WorkingHours are the working hours in a week, from Start to End o'clock on
+// weekdays in local time.
+type WorkingHours struct {
+	Start int
+	End   int
+}
+
+// Contains reports whether t is in the working hours in the location of t.
+func (w WorkingHours) Contains(t time.Time) bool {
+	if t.Weekday() == time.Saturday || t.Weekday() == time.Sunday {
+		return false
+	}
+	return t.Hour() >= w.Start && t.Hour() < w.End
+}
+
+type ActivitySummary struct {
+	TimeFrame
+
+	Created  Heatmap // creation of issues and pull requests
+	Comments Heatmap
+	Reviews  Heatmap
+	Merges   Heatmap
+
+	// Responders counts the first responses to issues and pull requests by
+	// responder.
+	Responders map[string]int
+
+	// OffHours counts the first responses given outside the working hours of
+	// the responders by responder.
+	OffHours map[string]int
+}
+
+func NewActivitySummary() *ActivitySummary {
+	return &ActivitySummary{
+		Responders: make(map[string]int),
+		OffHours:   make(map[string]int),
+	}
+}
+
+// Union merges the counts of other.
+func (s *ActivitySummary) Union(other *ActivitySummary) {
+	s.TimeFrame.Union(other.TimeFrame)
+	s.Created.Union(&other.Created)
+	s.Comments.Union(&other.Comments)
+	s.Reviews.Union(&other.Reviews)
+	s.Merges.Union(&other.Merges)
+	for responder, count := range other.Responders {
+		s.Responders[responder] += count
+	}
+	for responder, count := range other.OffHours {
+		s.OffHours[responder] += count
+	}
+}
+
+// OffHoursShare returns the share of first responses outside the working
+// hours of the responders.
+func (s *ActivitySummary) OffHoursShare() float64 {
+	total, offHours := 0, 0
+	for responder, count := range s.Responders {
+		total += count
+		offHours += s.OffHours[responder]
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(offHours) / float64(total)
+}
+
+type SummarizeActivityOptions struct {
+	TimeFrame TimeFrame
+	Issues    map[int]github.Issue
+	Comments  map[int][]github.IssueComment
+	Reviews   map[int][]github.PullRequestReview
+
+	// Location returns the time zone of the account. The actor of merges is
+	// unknown, so merges are located with the zero account.
+	Location func(account github.Account) *time.Location
+
+	// Maintainers restricts first responses to the maintainers if not empty.
+	Maintainers  set.Set[string]
+	WorkingHours WorkingHours
+
+	// Bots recognizes bots by login patterns if not nil.
+	Bots *actor.Classifier
+}
+
+// SummarizeActivity summarizes the activities in the time frame by day of
+// week and hour of day in the time zones of the actors.
+// The first response to an issue or a pull request created in the time frame
+// is the earliest comment or review by anyone other than the author and bots.
+func SummarizeActivity(opts SummarizeActivityOptions) *ActivitySummary {
+	// normalize maintainers
+	maintainers := set.New[string]()
+	for maintainer := range opts.Maintainers {
+		maintainers.Add(strings.ToLower(maintainer))
+	}
+	local := func(account github.Account, t time.Time) time.Time {
+		if opts.Location == nil {
+			return t.UTC()
+		}
+		return t.In(opts.Location(account))
+	}
+
+	summary := NewActivitySummary()
+	summary.TimeFrame = opts.TimeFrame
+	for number, issue := range opts.Issues {
+		if opts.TimeFrame.Contains(issue.CreatedAt) {
+			summary.Created.Add(local(issue.User, issue.CreatedAt))
+		}
+		if issue.Merged() && opts.TimeFrame.Contains(*issue.PullRequest.MergedAt) {
+			summary.Merges.Add(local(github.Account{}, *issue.PullRequest.MergedAt))
+		}
+
+		// collect responses
+		type response struct {
+			user github.Account
+			time time.Time
+		}
+		var responses []response
+		for _, comment := range opts.Comments[number] {
+			if opts.TimeFrame.Contains(comment.CreatedAt) {
+				summary.Comments.Add(local(comment.User, comment.CreatedAt))
+			}
+			responses = append(responses, response{comment.User, comment.CreatedAt})
+		}
+		for _, review := range opts.Reviews[number] {
+			if review.SubmittedAt.IsZero() {
+				continue // pending review
+			}
+			if opts.TimeFrame.Contains(review.SubmittedAt) {
+				summary.Reviews.Add(local(review.User, review.SubmittedAt))
+			}
+			responses = append(responses, response{review.User, review.SubmittedAt})
+		}
+
+		// first response
+		if issue.User.Login == "" || !opts.TimeFrame.Contains(issue.CreatedAt) {
+			continue
+		}
+		slices.SortFunc(responses, func(a, b response) int {
+			return a.time.Compare(b.time)
+		})
+		for _, r := range responses {
+			if opts.Bots.IsBot(r.user) || strings.EqualFold(r.user.Login, issue.User.Login) {
+				continue
+			}
+			if len(maintainers) > 0 && !maintainers.Contains(strings.ToLower(r.user.Login)) {
+				continue
+			}
+			summary.Responders[r.user.Login]++
+			if !opts.WorkingHours.Contains(local(r.user, r.time)) {
+				summary.OffHours[r.user.Login]++
+			}
+			break
+		}
+	}
+	return summary
+}
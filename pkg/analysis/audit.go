@@ -0,0 +1,198 @@
+package analysis
+
+import (
+	"strings"
+	"time"
+
+	"github.com/shizhMSFT/gha/pkg/actor"
+	"github.com/shizhMSFT/gha/pkg/container/set"
+	"github.com/shizhMSFT/gha/pkg/github"
+	"github.com/shizhMSFT/gha/pkg/identity"
+)
+
+// DismissedApproval is an approval dismissed after the merge.
+type DismissedApproval struct {
+	Reviewer    string
+	ApprovedAt  time.Time
+	DismissedBy string
+	DismissedAt time.Time
+}
+
+// MergeAudit is the audit record of a merged pull request.
+type MergeAudit struct {
+	Number   int
+	Author   string
+	MergedBy string // empty if unknown
+	MergedAt time.Time
+
+	Approvers          []string            // non-author approvers before the merge
+	DismissedApprovals []DismissedApproval // approvals dismissed after the merge
+	DismissedReviews   int                 // dismissed reviews with unknown dismissal before the merge
+}
+
+// Approved reports whether the pull request was approved by someone other
+// than the author when merged.
+func (a *MergeAudit) Approved() bool {
+	return len(a.Approvers) > 0 || len(a.DismissedApprovals) > 0
+}
+
+// AuditSummary audits the pull requests merged in the time frame.
+type AuditSummary struct {
+	TimeFrame
+
+	Audits         map[int]*MergeAudit // audit records by pull request
+	Violations     set.Set[int]        // merged without approval by someone other than the author
+	MissingReviews set.Set[int]        // merged without review data in the snapshot
+	SelfMerges     set.Set[int]        // merged by the author
+	Dismissed      set.Set[int]        // approvals dismissed after the merge
+	UnknownMergers set.Set[int]        // merged by unknown accounts
+}
+
+func NewAuditSummary() *AuditSummary {
+	return &AuditSummary{
+		Audits:         make(map[int]*MergeAudit),
+		Violations:     set.New[int](),
+		MissingReviews: set.New[int](),
+		SelfMerges:     set.New[int](),
+		Dismissed:      set.New[int](),
+		UnknownMergers: set.New[int](),
+	}
+}
+
+type SummarizeAuditOptions struct {
+	TimeFrame  TimeFrame
+	Issues     map[int]github.Issue
+	Reviews    map[int][]github.PullRequestReview
+	Timelines  map[int][]github.TimelineEvent // resolves mergers and dismissals if not nil
+	Identities *identity.Map                  // resolves authors, approvers and mergers to people if not nil
+	Bots       *actor.Classifier              // recognizes bots by login patterns if not nil
+}
+
+// SummarizeAudit audits the pull requests merged in the time frame, where a
+// pull request complies if it was approved before the merge by someone other
+// than the author. Only the latest approval or change request of each reviewer
+// before the merge counts, so an approval superseded by a change request is not
+// an approval. Reviews by bots are ignored, and reviews or merges by other
+// accounts of the author in the identities count as the author's. Reviewers
+// with several accounts in the identities are counted once.
+// Pull requests without an entry in the reviews are not audited for approvals
+// but reported as missing review data.
+// The merger is taken from `merged_by` of the pull request, or from the merged
+// event of the timeline.
+// A dismissed review only shows its state before dismissal in the timeline, so
+// an approval dismissed after the merge is counted as an approval if the
+// timeline is available, and otherwise counted as a dismissed review. A review
+// dismissed before the merge withdraws the vote of its reviewer.
+func SummarizeAudit(opts SummarizeAuditOptions) *AuditSummary {
+	summary := NewAuditSummary()
+	summary.TimeFrame = opts.TimeFrame
+	for number, issue := range opts.Issues {
+		if !issue.Merged() {
+			continue
+		}
+		mergedAt := *issue.PullRequest.MergedAt
+		if !opts.TimeFrame.Contains(mergedAt) {
+			continue
+		}
+		audit := &MergeAudit{
+			Number:   number,
+			Author:   issue.User.Login,
+			MergedAt: mergedAt,
+		}
+		if mergedBy := issue.PullRequest.MergedBy; mergedBy != nil {
+			audit.MergedBy = mergedBy.Login
+		}
+
+		// resolve mergers and dismissals by the timeline
+		dismissals := make(map[int64]github.TimelineEvent)
+		for _, event := range opts.Timelines[number] {
+			switch event.Event {
+			case github.EventMerged:
+				if audit.MergedBy == "" {
+					audit.MergedBy = event.Who().Login
+				}
+			case github.EventReviewDismissed:
+				if event.DismissedReview != nil {
+					dismissals[event.DismissedReview.ReviewID] = event
+				}
+			}
+		}
+
+		summary.Audits[number] = audit
+		if audit.MergedBy == "" {
+			summary.UnknownMergers.Add(number)
+		} else if opts.Identities.Same(audit.MergedBy, audit.Author) {
+			summary.SelfMerges.Add(number)
+		}
+		reviews, ok := opts.Reviews[number]
+		if !ok {
+			summary.MissingReviews.Add(number)
+			continue
+		}
+
+		// take the latest vote of each reviewer before the merge
+		type vote struct {
+			review    github.PullRequestReview
+			state     string
+			dismissal *github.TimelineEvent // dismissal after the merge
+		}
+		votes := make(map[string]vote)
+		var reviewers []string
+		for _, review := range reviews {
+			if review.SubmittedAt.IsZero() || review.SubmittedAt.After(mergedAt) {
+				continue
+			}
+			if opts.Bots.IsBot(review.User) || opts.Identities.Same(review.User.Login, issue.User.Login) {
+				continue
+			}
+			v := vote{review: review, state: review.State}
+			switch review.State {
+			case github.ReviewStateApproved, github.ReviewStateChangesRequested:
+			case github.ReviewStateDismissed:
+				event, ok := dismissals[review.ID]
+				if !ok {
+					audit.DismissedReviews++
+					continue
+				}
+				if event.When().After(mergedAt) {
+					v.state = strings.ToUpper(event.DismissedReview.State)
+					v.dismissal = &event
+				}
+			default:
+				continue
+			}
+			reviewer := opts.Identities.Person(review.User.Login)
+			last, ok := votes[reviewer]
+			if !ok {
+				reviewers = append(reviewers, reviewer)
+			} else if last.review.SubmittedAt.After(review.SubmittedAt) {
+				continue
+			}
+			votes[reviewer] = v
+		}
+		for _, reviewer := range reviewers {
+			v := votes[reviewer]
+			if v.state != github.ReviewStateApproved {
+				continue
+			}
+			if v.dismissal == nil {
+				audit.Approvers = append(audit.Approvers, v.review.User.Login)
+				continue
+			}
+			audit.DismissedApprovals = append(audit.DismissedApprovals, DismissedApproval{
+				Reviewer:    v.review.User.Login,
+				ApprovedAt:  v.review.SubmittedAt,
+				DismissedBy: v.dismissal.Who().Login,
+				DismissedAt: v.dismissal.When(),
+			})
+		}
+
+		if !audit.Approved() {
+			summary.Violations.Add(number)
+		}
+		if len(audit.DismissedApprovals) > 0 {
+			summary.Dismissed.Add(number)
+		}
+	}
+	return summary
+}
@@ -0,0 +1,169 @@
+package analysis
+
+import (
+	"time"
+
+	"github.com/shizhMSFT/gha/pkg/github"
+)
+
+// BacklogPoint is the state of a backlog at the end of a day.
+type BacklogPoint struct {
+	Date                 time.Time // start of the day
+	Arrivals             int       // items created on the day
+	Departures           int       // items closed on the day
+	CumulativeArrivals   int       // items created by the end of the day
+	CumulativeDepartures int       // items closed by the end of the day
+	Open                 int       // items open at the end of the day
+	TotalAge             time.Duration
+}
+
+// AverageAge returns the average age of the open items at the end of the day.
+func (p BacklogPoint) AverageAge() time.Duration {
+	if p.Open == 0 {
+		return 0
+	}
+	return p.TotalAge / time.Duration(p.Open)
+}
+
+// Add adds the other point of the same day.
+func (p *BacklogPoint) Add(other BacklogPoint) {
+	p.Arrivals += other.Arrivals
+	p.Departures += other.Departures
+	p.CumulativeArrivals += other.CumulativeArrivals
+	p.CumulativeDepartures += other.CumulativeDepartures
+	p.Open += other.Open
+	p.TotalAge += other.TotalAge
+}
+
+// Backlog is the cumulative flow of issues and pull requests by day.
+type Backlog struct {
+	TimeFrame
+
+	Issues       []BacklogPoint
+	PullRequests []BacklogPoint
+}
+
+// SummarizeBacklog rebuilds the daily backlog of issues and pull requests in
+// UTC days from the start to the end of the time frame, where open ends are
+// bounded by the earliest creation and now.
+// Cumulative counts include items created before the time frame.
+func SummarizeBacklog(issues map[int]github.Issue, timeFrame TimeFrame) *Backlog {
+	start, end := timeFrame.Start, timeFrame.End
+	if end.IsZero() {
+		end = time.Now()
+	}
+	if start.IsZero() {
+		start = end
+		for _, issue := range issues {
+			if issue.CreatedAt.Before(start) {
+				start = issue.CreatedAt
+			}
+		}
+	}
+	start = start.UTC().Truncate(24 * time.Hour)
+
+	backlog := &Backlog{TimeFrame: timeFrame}
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		dayEnd := day.AddDate(0, 0, 1)
+		issuePoint := BacklogPoint{Date: day}
+		pullRequestPoint := BacklogPoint{Date: day}
+		for _, issue := range issues {
+			point := &issuePoint
+			if issue.IsPullRequest() {
+				point = &pullRequestPoint
+			}
+			if !issue.CreatedAt.Before(dayEnd) {
+				continue
+			}
+			point.CumulativeArrivals++
+			if !issue.CreatedAt.Before(day) {
+				point.Arrivals++
+			}
+			if issue.ClosedAt != nil && issue.ClosedAt.Before(dayEnd) {
+				point.CumulativeDepartures++
+				if !issue.ClosedAt.Before(day) {
+					point.Departures++
+				}
+				continue
+			}
+			point.Open++
+			point.TotalAge += dayEnd.Sub(issue.CreatedAt)
+		}
+		backlog.Issues = append(backlog.Issues, issuePoint)
+		backlog.PullRequests = append(backlog.PullRequests, pullRequestPoint)
+	}
+	return backlog
+}
+
+// Union adds the other backlog of the same days.
+func (b *Backlog) Union(other *Backlog) {
+	b.TimeFrame.Union(other.TimeFrame)
+	b.Issues = unionBacklogPoints(b.Issues, other.Issues)
+	b.PullRequests = unionBacklogPoints(b.PullRequests, other.PullRequests)
+}
+
+func unionBacklogPoints(points, other []BacklogPoint) []BacklogPoint {
+	if len(points) == 0 {
+		return append(points, other...)
+	}
+	for i := range points {
+		if i < len(other) && points[i].Date.Equal(other[i].Date) {
+			points[i].Add(other[i])
+		}
+	}
+	return points
+}
+
+// FlowSummary summarizes the backlog points in a time bucket.
+type FlowSummary struct {
+	TimeFrame
+
+	Days          int
+	Arrivals      int
+	Departures    int
+	StartOpen     int // items open at the start of the bucket
+	EndOpen       int // items open at the end of the bucket
+	AverageOpen   float64
+	AverageAge    time.Duration // average age of items open at the end of the bucket
+	CycleTime     time.Duration // Little's law estimate of the time to close
+	HasCycleTime  bool
+	ArrivalRate   float64 // items created per day
+	DepartureRate float64 // items closed per day
+}
+
+// SummarizeFlow summarizes the backlog points in each time bucket.
+// The cycle time is estimated with Little's law as the average number of open
+// items divided by the average departure rate.
+func SummarizeFlow(points []BacklogPoint, buckets []TimeFrame) []*FlowSummary {
+	summaries := make([]*FlowSummary, 0, len(buckets))
+	for _, bucket := range buckets {
+		summary := &FlowSummary{TimeFrame: bucket}
+		totalOpen := 0
+		for _, point := range points {
+			if !bucket.Contains(point.Date) {
+				continue
+			}
+			if summary.Days == 0 {
+				summary.StartOpen = point.Open + point.Departures - point.Arrivals
+			}
+			summary.Days++
+			summary.Arrivals += point.Arrivals
+			summary.Departures += point.Departures
+			summary.EndOpen = point.Open
+			summary.AverageAge = point.AverageAge()
+			totalOpen += point.Open
+		}
+		if summary.Days > 0 {
+			days := float64(summary.Days)
+			summary.AverageOpen = float64(totalOpen) / days
+			summary.ArrivalRate = float64(summary.Arrivals) / days
+			summary.DepartureRate = float64(summary.Departures) / days
+			if summary.DepartureRate > 0 {
+				summary.CycleTime = time.Duration(summary.AverageOpen / summary.DepartureRate * float64(24*time.Hour))
+				summary.HasCycleTime = true
+			}
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
@@ -1,14 +1,15 @@
 package analysis
 
 import (
-	"cmp"
 	"slices"
 	"strings"
 	"time"
 
+	"github.com/shizhMSFT/gha/pkg/calendar"
 	"github.com/shizhMSFT/gha/pkg/container/set"
 	"github.com/shizhMSFT/gha/pkg/github"
 	"github.com/shizhMSFT/gha/pkg/sort"
+	"golang.org/x/exp/maps"
 )
 
 type IssueCommentSummary struct {
@@ -26,12 +27,24 @@ func NewIssueCommentSummary() *IssueCommentSummary {
 }
 
 type SummarizeIssueCommentsOptions struct {
-	TimeFrame   TimeFrame
-	Issues      map[int]github.Issue
-	Comments    map[int][]github.IssueComment
-	Maintainers set.Set[string]
+	TimeFrame      TimeFrame
+	Issues         map[int]github.Issue
+	Comments       map[int][]github.IssueComment
+	Reviews        map[int][]github.PullRequestReview
+	ReviewComments map[int][]github.PullRequestReviewComment
+	Maintainers    set.Set[string]
+	Calendar       *calendar.Calendar
+
+	// PullRequests summarizes pull requests instead of issues.
+	PullRequests bool
 }
 
+// SummarizeIssueComments summarizes the first responses by maintainers to the
+// issues created in the time frame by non-maintainers, or to the pull requests
+// if PullRequests is set.
+// The first response to a pull request is the earliest of an issue comment, a
+// submitted review and a review comment. Only the issues and pull requests in
+// the snapshots of comments, reviews or review comments are summarized.
 func SummarizeIssueComments(opts SummarizeIssueCommentsOptions) *IssueCommentSummary {
 	// normalize maintainers
 	maintainers := set.New[string]()
@@ -42,9 +55,18 @@ func SummarizeIssueComments(opts SummarizeIssueCommentsOptions) *IssueCommentSum
 	// summarize
 	summary := NewIssueCommentSummary()
 	summary.TimeFrame = opts.TimeFrame
-	for number, comments := range opts.Comments {
-		issue := opts.Issues[number]
-		if issue.IsPullRequest() {
+	numbers := set.New(maps.Keys(opts.Comments)...)
+	if opts.PullRequests {
+		for number := range opts.Reviews {
+			numbers.Add(number)
+		}
+		for number := range opts.ReviewComments {
+			numbers.Add(number)
+		}
+	}
+	for number := range numbers {
+		issue, ok := opts.Issues[number]
+		if !ok || issue.IsPullRequest() != opts.PullRequests {
 			continue
 		}
 		if !opts.TimeFrame.Contains(issue.CreatedAt) {
@@ -53,46 +75,37 @@ func SummarizeIssueComments(opts SummarizeIssueCommentsOptions) *IssueCommentSum
 		if maintainers.Contains(strings.ToLower(issue.User.Login)) {
 			continue
 		}
-		slices.SortFunc(comments, func(a, b github.IssueComment) int {
-			return a.CreatedAt.Compare(b.CreatedAt)
-		})
-		responded := false
-		for _, comment := range comments {
+		var responses []time.Time
+		for _, comment := range opts.Comments[number] {
 			if maintainers.Contains(strings.ToLower(comment.User.Login)) {
-				duration := comment.CreatedAt.Sub(issue.CreatedAt)
-				summary.Responded[number] = duration
-				responded = true
-				break
+				responses = append(responses, comment.CreatedAt)
+			}
+		}
+		if opts.PullRequests {
+			for _, review := range opts.Reviews[number] {
+				if !review.SubmittedAt.IsZero() && maintainers.Contains(strings.ToLower(review.User.Login)) {
+					responses = append(responses, review.SubmittedAt)
+				}
+			}
+			for _, comment := range opts.ReviewComments[number] {
+				if maintainers.Contains(strings.ToLower(comment.User.Login)) {
+					responses = append(responses, comment.CreatedAt)
+				}
 			}
 		}
-		if !responded {
+		if len(responses) == 0 {
 			summary.NoResponse[number] = issue.CreatedAt
+			continue
 		}
+		first := slices.MinFunc(responses, time.Time.Compare)
+		summary.Responded[number] = opts.Calendar.Duration(issue.CreatedAt, first)
 	}
 	return summary
 }
 
-func (s *IssueCommentSummary) OutOfSLA(sla time.Duration, now time.Time) sort.MapEntrySlice[int, time.Duration] {
-	var durations sort.MapEntrySlice[int, time.Duration]
-	for number, duration := range s.Responded {
-		if duration > sla {
-			durations = append(durations, sort.MapEntry[int, time.Duration]{
-				Key:   number,
-				Value: duration,
-			})
-		}
-	}
-	for number, createdAt := range s.NoResponse {
-		duration := now.Sub(createdAt)
-		if duration > sla {
-			durations = append(durations, sort.MapEntry[int, time.Duration]{
-				Key:   number,
-				Value: duration,
-			})
-		}
-	}
-	durations.Sort(func(a, b sort.MapEntry[int, time.Duration]) int {
-		return cmp.Compare(b.Value, a.Value)
-	})
-	return durations
+// OutOfSLA returns the items waiting for the first response longer than sla,
+// including the ones not responded yet, where the waiting time is measured in
+// the working time of cal.
+func (s *IssueCommentSummary) OutOfSLA(sla time.Duration, now time.Time, cal *calendar.Calendar) sort.MapEntrySlice[int, time.Duration] {
+	return outOfSLA(s.Responded, s.NoResponse, sla, now, cal)
 }
@@ -1,6 +1,16 @@
 package analysis
 
-import "time"
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shizhMSFT/gha/pkg/calendar"
+	"github.com/shizhMSFT/gha/pkg/github"
+	"github.com/shizhMSFT/gha/pkg/sort"
+)
 
 type TimeFrame struct {
 	Start time.Time
@@ -23,3 +33,152 @@ func (tf *TimeFrame) Union(other TimeFrame) {
 func (tf *TimeFrame) Contains(t time.Time) bool {
 	return (tf.Start.IsZero() || !t.Before(tf.Start)) && (tf.End.IsZero() || !t.After(tf.End))
 }
+
+// BucketSize is the size of time buckets.
+type BucketSize string
+
+// Supported bucket sizes.
+const (
+	BucketWeek    BucketSize = "week"
+	BucketMonth   BucketSize = "month"
+	BucketQuarter BucketSize = "quarter"
+)
+
+// ParseBucketSize parses a bucket size.
+func ParseBucketSize(size string) (BucketSize, error) {
+	switch bucketSize := BucketSize(strings.ToLower(size)); bucketSize {
+	case BucketWeek, BucketMonth, BucketQuarter:
+		return bucketSize, nil
+	default:
+		return "", fmt.Errorf("invalid bucket size: %s", size)
+	}
+}
+
+// Truncate returns the start of the bucket containing t.
+// Weeks start on Monday.
+func (b BucketSize) Truncate(t time.Time) time.Time {
+	year, month, day := t.Date()
+	switch b {
+	case BucketWeek:
+		weekday := (int(t.Weekday()) + 6) % 7 // days since Monday
+		return time.Date(year, month, day-weekday, 0, 0, 0, 0, t.Location())
+	case BucketMonth:
+		return time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+	case BucketQuarter:
+		return time.Date(year, month-(month-1)%3, 1, 0, 0, 0, 0, t.Location())
+	default:
+		panic("invalid bucket size")
+	}
+}
+
+// Next returns the start of the bucket following the bucket starting at t.
+func (b BucketSize) Next(t time.Time) time.Time {
+	switch b {
+	case BucketWeek:
+		return t.AddDate(0, 0, 7)
+	case BucketMonth:
+		return t.AddDate(0, 1, 0)
+	case BucketQuarter:
+		return t.AddDate(0, 3, 0)
+	default:
+		panic("invalid bucket size")
+	}
+}
+
+// Label returns the name of the bucket containing t, such as `2023-W05`,
+// `2023-01`, or `2023-Q1`.
+func (b BucketSize) Label(t time.Time) string {
+	switch b {
+	case BucketWeek:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case BucketMonth:
+		return t.Format("2006-01")
+	case BucketQuarter:
+		return fmt.Sprintf("%d-Q%d", t.Year(), (int(t.Month())-1)/3+1)
+	default:
+		panic("invalid bucket size")
+	}
+}
+
+// Buckets splits the time frame into consecutive buckets aligned to the
+// calendar, where first and last bound an open start or end of the time
+// frame.
+// Buckets do not overlap, and the first and the last buckets are clipped to
+// the time frame. There are no buckets if both the start and first are zero.
+func (tf *TimeFrame) Buckets(size BucketSize, first, last time.Time) []TimeFrame {
+	start, end := tf.Start, tf.End
+	if start.IsZero() {
+		start = first
+	}
+	if start.IsZero() {
+		return nil
+	}
+	if end.IsZero() {
+		end = last
+	}
+	var buckets []TimeFrame
+	for bucketStart := size.Truncate(start); bucketStart.Before(end); {
+		next := size.Next(bucketStart)
+		bucket := TimeFrame{
+			Start: bucketStart,
+			End:   next.Add(-time.Nanosecond),
+		}
+		if bucket.Start.Before(start) {
+			bucket.Start = start
+		}
+		if bucket.End.After(end) {
+			bucket.End = end
+		}
+		buckets = append(buckets, bucket)
+		bucketStart = next
+	}
+	return buckets
+}
+
+// IssueDuration returns the time to close the issue, or the age of the open
+// issue, in the working time of the calendar.
+func IssueDuration(issue github.Issue, cal *calendar.Calendar) time.Duration {
+	if issue.ClosedAt == nil {
+		return cal.Since(issue.CreatedAt)
+	}
+	return cal.Duration(issue.CreatedAt, *issue.ClosedAt)
+}
+
+// outOfSLA returns the items waiting longer than sla, sorted by the waiting
+// time in descending order.
+// The waiting time of pending items is measured until now in the working time
+// of the calendar.
+func outOfSLA(done map[int]time.Duration, pending map[int]time.Time, sla time.Duration, now time.Time, cal *calendar.Calendar) sort.MapEntrySlice[int, time.Duration] {
+	var durations sort.MapEntrySlice[int, time.Duration]
+	for number, duration := range done {
+		if duration > sla {
+			durations = append(durations, sort.MapEntry[int, time.Duration]{
+				Key:   number,
+				Value: duration,
+			})
+		}
+	}
+	for number, createdAt := range pending {
+		duration := cal.Duration(createdAt, now)
+		if duration > sla {
+			durations = append(durations, sort.MapEntry[int, time.Duration]{
+				Key:   number,
+				Value: duration,
+			})
+		}
+	}
+	durations.Sort(func(a, b sort.MapEntry[int, time.Duration]) int {
+		return cmp.Compare(b.Value, a.Value)
+	})
+	return durations
+}
+
+// sortEvents returns a copy of the timeline events sorted by time.
+func sortEvents(events []github.TimelineEvent) []github.TimelineEvent {
+	sorted := slices.Clone(events)
+	slices.SortStableFunc(sorted, func(a, b github.TimelineEvent) int {
+		return a.When().Compare(b.When())
+	})
+	return sorted
+}
@@ -0,0 +1,51 @@
+package analysis
+
+import (
+	"github.com/shizhMSFT/gha/pkg/math"
+	"golang.org/x/exp/maps"
+)
+
+// BusFactorShare is the share of contributions covered by the bus factor.
+const BusFactorShare = 0.5
+
+// TopContributors is the number of top contributors in Concentration.TopShare.
+const TopContributors = 3
+
+// Concentration measures how contributions concentrate on a few people.
+type Concentration struct {
+	Total        int     // total contributions
+	Contributors int     // people with contributions
+	BusFactor    int     // fewest people covering BusFactorShare of contributions
+	Gini         float64 // Gini coefficient of contributions by person
+	TopShare     float64 // share of contributions by the TopContributors people
+}
+
+// NewConcentration measures the concentration of contributions counted by
+// person. People without contributions are ignored.
+func NewConcentration(counts map[string]int) *Concentration {
+	values := make([]int, 0, len(counts))
+	for _, count := range maps.Values(counts) {
+		if count > 0 {
+			values = append(values, count)
+		}
+	}
+	c := &Concentration{
+		Contributors: len(values),
+		BusFactor:    math.BusFactor(values, BusFactorShare),
+		Gini:         math.Gini(values),
+		TopShare:     math.TopShare(values, TopContributors),
+	}
+	for _, value := range values {
+		c.Total += value
+	}
+	return c
+}
+
+// MergedPullRequests returns the merged pull request counts by author.
+func (s *Summary) MergedPullRequests() map[string]int {
+	counts := make(map[string]int)
+	for author, summary := range s.Authors {
+		counts[author] = summary.PullRequest.Merged
+	}
+	return counts
+}
@@ -0,0 +1,208 @@
+package analysis
+
+import (
+	"slices"
+	"time"
+
+	"github.com/shizhMSFT/gha/pkg/actor"
+	"github.com/shizhMSFT/gha/pkg/container/set"
+	"github.com/shizhMSFT/gha/pkg/github"
+	"github.com/shizhMSFT/gha/pkg/identity"
+)
+
+// PullRequestOutcome summarizes the outcome of pull requests.
+type PullRequestOutcome struct {
+	Total     int
+	Merged    int
+	Closed    int             // closed without merge
+	Durations []time.Duration // time to merge
+}
+
+// MergeRate returns the ratio of merged pull requests among the resolved ones,
+// or false if no pull request is resolved.
+func (o *PullRequestOutcome) MergeRate() (float64, bool) {
+	resolved := o.Merged + o.Closed
+	if resolved == 0 {
+		return 0, false
+	}
+	return float64(o.Merged) / float64(resolved), true
+}
+
+func (o *PullRequestOutcome) add(issue github.Issue) {
+	o.Total++
+	switch {
+	case issue.Merged():
+		o.Merged++
+		o.Durations = append(o.Durations, issue.Duration())
+	case issue.State == "closed":
+		o.Closed++
+	}
+}
+
+// ContributorSummary summarizes new and returning contributors in a time
+// frame.
+type ContributorSummary struct {
+	TimeFrame
+
+	New       set.Set[string] // contributors with the first pull request in the time frame
+	Returning set.Set[string] // contributors with earlier pull requests
+
+	FirstPullRequests   PullRequestOutcome // first pull requests of new contributors
+	VeteranPullRequests PullRequestOutcome // pull requests of returning contributors
+}
+
+// Contributors returns the number of contributors in the time frame.
+func (s *ContributorSummary) Contributors() int {
+	return s.New.Len() + s.Returning.Len()
+}
+
+// contribution is a pull request by a contributor.
+type contribution struct {
+	contributor string
+	issue       github.Issue
+}
+
+// contributions returns the pull requests in the snapshots by contributors
+// resolved by the identities, sorted by the creation time.
+// A pull request in several snapshots of the same repository is counted once
+// in its latest update. Pull requests by bots are ignored.
+func contributions(snapshots []map[int]github.Issue, identities *identity.Map, bots *actor.Classifier) []contribution {
+	latest := make(map[IssueReference]github.Issue)
+	for _, snapshot := range snapshots {
+		for _, issue := range snapshot {
+			if !issue.IsPullRequest() || bots.IsBot(issue.User) {
+				continue
+			}
+			ref := Reference(issue)
+			if other, ok := latest[ref]; ok && !issue.UpdatedAt.After(other.UpdatedAt) {
+				continue
+			}
+			latest[ref] = issue
+		}
+	}
+	contributions := make([]contribution, 0, len(latest))
+	for _, issue := range latest {
+		contributions = append(contributions, contribution{
+			contributor: identities.Person(issue.User.Login),
+			issue:       issue,
+		})
+	}
+	slices.SortFunc(contributions, func(a, b contribution) int {
+		return a.issue.CreatedAt.Compare(b.issue.CreatedAt)
+	})
+	return contributions
+}
+
+// SummarizeContributors summarizes the new and returning contributors in each
+// time bucket over the full history of pull requests in the snapshots, where
+// contributors are resolved by the identities.
+// A contributor is new in the bucket of the first pull request across all the
+// snapshots, and only the first pull request is counted as the first pull
+// request of a new contributor. Pull requests by bots recognized by bots are
+// ignored.
+func SummarizeContributors(snapshots []map[int]github.Issue, buckets []TimeFrame, identities *identity.Map, bots *actor.Classifier) []*ContributorSummary {
+	summaries := make([]*ContributorSummary, len(buckets))
+	for i, bucket := range buckets {
+		summaries[i] = &ContributorSummary{
+			TimeFrame: bucket,
+			New:       set.New[string](),
+			Returning: set.New[string](),
+		}
+	}
+	first := make(map[string]time.Time)
+	for _, c := range contributions(snapshots, identities, bots) {
+		firstTime, seen := first[c.contributor]
+		if !seen {
+			first[c.contributor] = c.issue.CreatedAt
+		}
+		for _, summary := range summaries {
+			if !summary.Contains(c.issue.CreatedAt) {
+				continue
+			}
+			switch {
+			case !seen:
+				summary.New.Add(c.contributor)
+				summary.FirstPullRequests.add(c.issue)
+			case summary.Contains(firstTime):
+				// subsequent pull requests of a new contributor
+			default:
+				summary.Returning.Add(c.contributor)
+				summary.VeteranPullRequests.add(c.issue)
+			}
+		}
+	}
+	return summaries
+}
+
+// Cohort is the retention of the contributors with the first pull requests in
+// the same time bucket.
+type Cohort struct {
+	TimeFrame
+
+	Members set.Set[string]
+
+	// Active counts the members contributing in each bucket since the cohort
+	// bucket, where Active[0] is the size of the cohort.
+	Active []int
+}
+
+// Retention returns the share of members contributing n buckets after the
+// cohort bucket, or false if the bucket has not started by now.
+func (c *Cohort) Retention(n int) (float64, bool) {
+	if n >= len(c.Active) || c.Members.Len() == 0 {
+		return 0, false
+	}
+	return float64(c.Active[n]) / float64(c.Members.Len()), true
+}
+
+// SummarizeCohorts groups contributors into cohorts by the bucket of their
+// first pull requests across the snapshots, and counts the cohort members
+// contributing in each following bucket until now.
+func SummarizeCohorts(snapshots []map[int]github.Issue, size BucketSize, identities *identity.Map, bots *actor.Classifier, now time.Time) []*Cohort {
+	contributions := contributions(snapshots, identities, bots)
+	if len(contributions) == 0 {
+		return nil
+	}
+	start := size.Truncate(contributions[0].issue.CreatedAt)
+	var starts []time.Time
+	for t := start; !t.After(now); t = size.Next(t) {
+		starts = append(starts, t)
+	}
+	index := func(t time.Time) int {
+		i, found := slices.BinarySearchFunc(starts, t, time.Time.Compare)
+		if !found {
+			i--
+		}
+		return i
+	}
+
+	cohorts := make([]*Cohort, len(starts))
+	for i, t := range starts {
+		cohorts[i] = &Cohort{
+			TimeFrame: TimeFrame{Start: t, End: size.Next(t).Add(-time.Nanosecond)},
+			Members:   set.New[string](),
+			Active:    make([]int, len(starts)-i),
+		}
+	}
+	cohortOf := make(map[string]int)
+	active := make(map[string]set.Set[int]) // buckets with contributions by contributor
+	for _, c := range contributions {
+		i := index(c.issue.CreatedAt)
+		if i < 0 || i >= len(starts) {
+			continue
+		}
+		if _, ok := cohortOf[c.contributor]; !ok {
+			cohortOf[c.contributor] = i
+			cohorts[i].Members.Add(c.contributor)
+			active[c.contributor] = set.New[int]()
+		}
+		active[c.contributor].Add(i)
+	}
+	for contributor, buckets := range active {
+		cohort := cohortOf[contributor]
+		for i := range buckets {
+			cohorts[cohort].Active[i-cohort]++
+		}
+	}
+	return cohorts
+}
@@ -0,0 +1,99 @@
+package analysis
+
+import (
+	"strings"
+
+	"github.com/shizhMSFT/gha/pkg/calendar"
+	"github.com/shizhMSFT/gha/pkg/github"
+)
+
+// NoGroup is the group of items not belonging to any group.
+const NoGroup = "(none)"
+
+// SummarizeGroups summarizes the issues and pull requests created in the time
+// frame by the groups they belong to.
+// An item may belong to multiple groups, and items belonging to no group are
+// summarized in NoGroup. Durations are measured in the working time of the
+// calendar.
+func SummarizeGroups(issues map[int]github.Issue, timeFrame TimeFrame, cal *calendar.Calendar, group func(github.Issue) []string) map[string]*RepositorySummary {
+	members := make(map[string]map[int]github.Issue)
+	for number, issue := range issues {
+		groups := group(issue)
+		if len(groups) == 0 {
+			groups = []string{NoGroup}
+		}
+		for _, name := range groups {
+			if members[name] == nil {
+				members[name] = make(map[int]github.Issue)
+			}
+			members[name][number] = issue
+		}
+	}
+	summaries := make(map[string]*RepositorySummary)
+	for name, issues := range members {
+		summary := Summarize(issues, timeFrame, nil, cal)
+		if summary.Issue.Total+summary.PullRequest.Total == 0 {
+			continue
+		}
+		summaries[name] = summary.RepositorySummary
+	}
+	return summaries
+}
+
+// UnionGroups merges the group summaries of src into dst.
+func UnionGroups(dst, src map[string]*RepositorySummary) {
+	for name, other := range src {
+		summary := dst[name]
+		if summary == nil {
+			summary = NewRepositorySummary()
+			dst[name] = summary
+		}
+		summary.Union(other)
+	}
+}
+
+// LabelCategorizer maps issue labels to categories.
+type LabelCategorizer struct {
+	// Prefix selects the labels with the prefix and strips the prefix, such
+	// as `kind/` for `kind/bug`.
+	Prefix string
+
+	// Categories maps categories to labels. If set, only mapped labels are
+	// categorized and Prefix is ignored.
+	Categories map[string][]string
+}
+
+// Categorize returns the distinct categories of the labels.
+// Labels are matched case-insensitively.
+func (c *LabelCategorizer) Categorize(labels []github.Label) []string {
+	var categories []string
+	seen := make(map[string]struct{})
+	add := func(category string) {
+		if _, ok := seen[category]; ok {
+			return
+		}
+		seen[category] = struct{}{}
+		categories = append(categories, category)
+	}
+	for _, label := range labels {
+		name := label.Name
+		if len(c.Categories) > 0 {
+			for category, labels := range c.Categories {
+				for _, label := range labels {
+					if strings.EqualFold(label, name) {
+						add(category)
+					}
+				}
+			}
+			continue
+		}
+		if c.Prefix != "" {
+			if len(name) < len(c.Prefix) || !strings.EqualFold(name[:len(c.Prefix)], c.Prefix) {
+				continue
+			}
+			name = name[len(c.Prefix):]
+		}
+		add(name)
+	}
+	return categories
+}
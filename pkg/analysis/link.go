@@ -0,0 +1,292 @@
+package analysis
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shizhMSFT/gha/pkg/calendar"
+	"github.com/shizhMSFT/gha/pkg/container/set"
+	"github.com/shizhMSFT/gha/pkg/github"
+)
+
+// closingReference matches the closing keywords followed by an issue
+// reference, such as `fixes #123`, `closes org/repo#45` or
+// `resolves https://github.com/org/repo/issues/67`.
+var closingReference = regexp.MustCompile(`(?i)\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?):?\s+(?:https://github\.com/([\w.-]+/[\w.-]+)/issues/|([\w.-]+/[\w.-]+)?#)(\d+)\b`)
+
+// IssueReference is a reference to an issue.
+type IssueReference struct {
+	Repository string // org/repo, or empty for the same repository
+	Number     int
+}
+
+// ParseClosingReferences returns the issues referenced by closing keywords in
+// the text.
+func ParseClosingReferences(text string) []IssueReference {
+	var refs []IssueReference
+	for _, match := range closingReference.FindAllStringSubmatch(text, -1) {
+		number, err := strconv.Atoi(match[3])
+		if err != nil {
+			continue
+		}
+		repository := match[1]
+		if repository == "" {
+			repository = match[2]
+		}
+		refs = append(refs, IssueReference{Repository: repository, Number: number})
+	}
+	return refs
+}
+
+// repository returns the org/repo of the issue from its HTML URL.
+func repository(issue github.Issue) string {
+	path, ok := strings.CutPrefix(issue.HTMLURL, "https://github.com/")
+	if !ok {
+		return ""
+	}
+	parts := strings.SplitN(path, "/", 3)
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[0] + "/" + parts[1]
+}
+
+// Link sources.
+const (
+	LinkClosingReference = "closing reference" // closing keyword in the pull request body
+	LinkTimeline         = "timeline"          // cross-referenced pull request merged when the issue was closed
+)
+
+// linkCloseWindow is the tolerance between the merge of a cross-referenced
+// pull request and the close of the issue to link them by timelines.
+const linkCloseWindow = time.Minute
+
+// Link is a link from an issue to a pull request fixing it.
+type Link struct {
+	Issue       IssueReference
+	PullRequest IssueReference
+}
+
+// LinkGraph links issues to the pull requests fixing them across
+// repositories, where references are qualified by lowercase repositories.
+type LinkGraph struct {
+	Items   map[IssueReference]github.Issue            // issues and pull requests in the snapshots
+	FixedBy map[IssueReference]set.Set[IssueReference] // pull requests by issue
+	Fixes   map[IssueReference]set.Set[IssueReference] // issues by pull request
+	Sources map[Link]set.Set[string]                   // sources by link
+}
+
+func NewLinkGraph() *LinkGraph {
+	return &LinkGraph{
+		Items:   make(map[IssueReference]github.Issue),
+		FixedBy: make(map[IssueReference]set.Set[IssueReference]),
+		Fixes:   make(map[IssueReference]set.Set[IssueReference]),
+		Sources: make(map[Link]set.Set[string]),
+	}
+}
+
+// Link links the issue to the pull request fixing it.
+func (g *LinkGraph) Link(issue, pullRequest IssueReference, source string) {
+	if g.FixedBy[issue] == nil {
+		g.FixedBy[issue] = set.New[IssueReference]()
+	}
+	g.FixedBy[issue].Add(pullRequest)
+	if g.Fixes[pullRequest] == nil {
+		g.Fixes[pullRequest] = set.New[IssueReference]()
+	}
+	g.Fixes[pullRequest].Add(issue)
+	link := Link{Issue: issue, PullRequest: pullRequest}
+	if g.Sources[link] == nil {
+		g.Sources[link] = set.New[string]()
+	}
+	g.Sources[link].Add(source)
+}
+
+// Reference returns the repository-qualified reference to the issue.
+func Reference(issue github.Issue) IssueReference {
+	return IssueReference{
+		Repository: strings.ToLower(repository(issue)),
+		Number:     issue.Number,
+	}
+}
+
+// isIssue reports whether the reference is an issue in the snapshots.
+func (g *LinkGraph) isIssue(ref IssueReference) bool {
+	issue, ok := g.Items[ref]
+	return ok && !issue.IsPullRequest()
+}
+
+// BuildLinkGraph links the issues to the pull requests in the snapshots by the
+// closing references in the pull request bodies, and by the timelines of the
+// issues if not nil, where an issue is linked to a cross-referencing pull
+// request merged when the issue was closed. Timelines are in the same order as
+// the snapshots. References to other repositories are resolved across the
+// snapshots, and references to repositories without a snapshot are ignored.
+func BuildLinkGraph(snapshots []map[int]github.Issue, timelines []map[int][]github.TimelineEvent) *LinkGraph {
+	graph := NewLinkGraph()
+	repositories := set.New[string]()
+	for _, issues := range snapshots {
+		for _, issue := range issues {
+			ref := Reference(issue)
+			graph.Items[ref] = issue
+			repositories.Add(ref.Repository)
+		}
+	}
+
+	// link by closing references
+	for _, issues := range snapshots {
+		for _, pullRequest := range issues {
+			if !pullRequest.IsPullRequest() {
+				continue
+			}
+			from := Reference(pullRequest)
+			for _, ref := range ParseClosingReferences(pullRequest.Body) {
+				ref.Repository = strings.ToLower(ref.Repository)
+				if ref.Repository == "" {
+					ref.Repository = from.Repository
+				}
+				if graph.isIssue(ref) {
+					graph.Link(ref, from, LinkClosingReference)
+				}
+			}
+		}
+	}
+
+	// link by timelines
+	for i, issueTimelines := range timelines {
+		for number, events := range issueTimelines {
+			item, ok := snapshots[i][number]
+			if !ok || item.IsPullRequest() {
+				continue
+			}
+			issue := Reference(item)
+			var closedAt []time.Time
+			for _, event := range events {
+				if event.Event == github.EventClosed {
+					closedAt = append(closedAt, event.When())
+				}
+			}
+			for _, event := range events {
+				if event.Event != github.EventCrossReferenced || event.Source == nil || event.Source.Issue == nil {
+					continue
+				}
+				ref := Reference(*event.Source.Issue)
+				if !repositories.Contains(ref.Repository) {
+					continue
+				}
+				source, ok := graph.Items[ref]
+				if !ok {
+					// pull requests out of the snapshots
+					source = *event.Source.Issue
+				}
+				if !source.IsPullRequest() || !source.Merged() {
+					continue
+				}
+				mergedAt := *source.PullRequest.MergedAt
+				for _, t := range closedAt {
+					if d := t.Sub(mergedAt); d >= -linkCloseWindow && d <= linkCloseWindow {
+						graph.Items[ref] = source
+						graph.Link(issue, ref, LinkTimeline)
+						break
+					}
+				}
+			}
+		}
+	}
+	return graph
+}
+
+// LinkSummary summarizes the links between the issues of a repository and the
+// pull requests created in the time frame.
+type LinkSummary struct {
+	TimeFrame
+
+	Links   set.Set[Link]            // links of which the issue or the pull request is in the time frame
+	Sources map[string]set.Set[Link] // links by source
+
+	LeadTimes            map[int]time.Duration // duration from issue creation to the first merged fix by issue
+	ClosedWithoutFix     set.Set[int]          // closed issues without a merged pull request
+	UnlinkedPullRequests set.Set[int]          // merged pull requests without a linked issue
+	MergedPullRequests   int
+	ClosedIssues         int
+}
+
+func NewLinkSummary() *LinkSummary {
+	return &LinkSummary{
+		Links:                set.New[Link](),
+		Sources:              make(map[string]set.Set[Link]),
+		LeadTimes:            make(map[int]time.Duration),
+		ClosedWithoutFix:     set.New[int](),
+		UnlinkedPullRequests: set.New[int](),
+	}
+}
+
+type SummarizeLinksOptions struct {
+	TimeFrame TimeFrame
+	Issues    map[int]github.Issue // issues and pull requests of the repository
+	Graph     *LinkGraph           // links built from the snapshots of all the repositories
+	Calendar  *calendar.Calendar
+}
+
+// SummarizeLinks summarizes the lead time from the creation of the issues to
+// the merge of the first pull requests fixing them, the issues closed without
+// a fix, and the merged pull requests without a linked issue, where issues and
+// pull requests are created in the time frame. Links are counted for the
+// issues of the repository.
+func SummarizeLinks(opts SummarizeLinksOptions) *LinkSummary {
+	summary := NewLinkSummary()
+	summary.TimeFrame = opts.TimeFrame
+	graph := opts.Graph
+	for number, issue := range opts.Issues {
+		ref := Reference(issue)
+		if !issue.IsPullRequest() {
+			for pullRequest := range graph.FixedBy[ref] {
+				if !opts.TimeFrame.Contains(issue.CreatedAt) && !opts.TimeFrame.Contains(graph.Items[pullRequest].CreatedAt) {
+					continue
+				}
+				link := Link{Issue: ref, PullRequest: pullRequest}
+				summary.Links.Add(link)
+				for source := range graph.Sources[link] {
+					if summary.Sources[source] == nil {
+						summary.Sources[source] = set.New[Link]()
+					}
+					summary.Sources[source].Add(link)
+				}
+			}
+		}
+		if !opts.TimeFrame.Contains(issue.CreatedAt) {
+			continue
+		}
+		if issue.IsPullRequest() {
+			if !issue.Merged() {
+				continue
+			}
+			summary.MergedPullRequests++
+			if graph.Fixes[ref].Len() == 0 {
+				summary.UnlinkedPullRequests.Add(number)
+			}
+			continue
+		}
+
+		var fixedAt time.Time
+		for pullRequest := range graph.FixedBy[ref] {
+			if pr := graph.Items[pullRequest]; pr.Merged() {
+				if mergedAt := *pr.PullRequest.MergedAt; fixedAt.IsZero() || mergedAt.Before(fixedAt) {
+					fixedAt = mergedAt
+				}
+			}
+		}
+		if !fixedAt.IsZero() {
+			summary.LeadTimes[number] = opts.Calendar.Duration(issue.CreatedAt, fixedAt)
+		}
+		if issue.State == "closed" {
+			summary.ClosedIssues++
+			if fixedAt.IsZero() {
+				summary.ClosedWithoutFix.Add(number)
+			}
+		}
+	}
+	return summary
+}
@@ -0,0 +1,142 @@
+package analysis
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/shizhMSFT/gha/pkg/actor"
+	"github.com/shizhMSFT/gha/pkg/container/set"
+	"github.com/shizhMSFT/gha/pkg/github"
+	"github.com/shizhMSFT/gha/pkg/identity"
+)
+
+// ReviewMatrix counts the pull requests of each author reviewed by each
+// reviewer.
+type ReviewMatrix struct {
+	TimeFrame
+
+	Authors map[string]int            // pull request counts by author
+	Pairs   map[string]map[string]int // reviewed pull request counts by author and reviewer
+}
+
+func NewReviewMatrix() *ReviewMatrix {
+	return &ReviewMatrix{
+		Authors: make(map[string]int),
+		Pairs:   make(map[string]map[string]int),
+	}
+}
+
+// SummarizeReviewMatrix builds the author-reviewer matrix of the pull requests
+// created in the time frame.
+// Authors and reviewers are resolved to people by the identities.
+// Self-reviews and reviews by bots, recognized by the classifier, are not
+// counted.
+func SummarizeReviewMatrix(issues map[int]github.Issue, reviews map[int][]github.PullRequestReview, timeFrame TimeFrame, identities *identity.Map, bots *actor.Classifier) *ReviewMatrix {
+	matrix := NewReviewMatrix()
+	matrix.TimeFrame = timeFrame
+	for number, issue := range issues {
+		if !issue.IsPullRequest() {
+			continue
+		}
+		if !timeFrame.Contains(issue.CreatedAt) {
+			continue
+		}
+		author := identities.Person(issue.User.Login)
+		matrix.Authors[author]++
+		reviewers := set.New[string]()
+		for _, review := range reviews[number] {
+			if review.SubmittedAt.IsZero() {
+				continue
+			}
+			reviewer := identities.Person(review.User.Login)
+			if bots.IsBot(review.User) || strings.EqualFold(reviewer, author) {
+				continue
+			}
+			reviewers.Add(reviewer)
+		}
+		if reviewers.Len() == 0 {
+			continue
+		}
+		pairs := matrix.Pairs[author]
+		if pairs == nil {
+			pairs = make(map[string]int)
+			matrix.Pairs[author] = pairs
+		}
+		for reviewer := range reviewers {
+			pairs[reviewer]++
+		}
+	}
+	return matrix
+}
+
+func (m *ReviewMatrix) Union(other *ReviewMatrix) {
+	m.TimeFrame.Union(other.TimeFrame)
+	for author, count := range other.Authors {
+		m.Authors[author] += count
+	}
+	for author, other := range other.Pairs {
+		pairs := m.Pairs[author]
+		if pairs == nil {
+			pairs = make(map[string]int)
+			m.Pairs[author] = pairs
+		}
+		for reviewer, count := range other {
+			pairs[reviewer] += count
+		}
+	}
+}
+
+// Count returns the number of pull requests of the author reviewed by the
+// reviewer.
+func (m *ReviewMatrix) Count(author, reviewer string) int {
+	return m.Pairs[author][reviewer]
+}
+
+// Share returns the share of the pull requests of the author reviewed by the
+// reviewer.
+func (m *ReviewMatrix) Share(author, reviewer string) float64 {
+	total := m.Authors[author]
+	if total == 0 {
+		return 0
+	}
+	return float64(m.Count(author, reviewer)) / float64(total)
+}
+
+// ReviewerTotals returns the number of reviewed pull requests by reviewer.
+func (m *ReviewMatrix) ReviewerTotals() map[string]int {
+	totals := make(map[string]int)
+	for _, pairs := range m.Pairs {
+		for reviewer, count := range pairs {
+			totals[reviewer] += count
+		}
+	}
+	return totals
+}
+
+// SortedAuthors returns the authors sorted by pull request counts in
+// descending order.
+func (m *ReviewMatrix) SortedAuthors() []string {
+	return sortedKeys(m.Authors)
+}
+
+// SortedReviewers returns the reviewers sorted by reviewed pull request counts
+// in descending order.
+func (m *ReviewMatrix) SortedReviewers() []string {
+	return sortedKeys(m.ReviewerTotals())
+}
+
+// sortedKeys returns the keys sorted by the counts in descending order, and
+// then by the keys in ascending order.
+func sortedKeys(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b string) int {
+		if counts[a] != counts[b] {
+			return counts[b] - counts[a]
+		}
+		return strings.Compare(a, b)
+	})
+	return keys
+}
@@ -0,0 +1,175 @@
+package analysis
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/shizhMSFT/gha/pkg/github"
+)
+
+// BurndownPoint is the scope and the remaining items of a milestone at a time.
+type BurndownPoint struct {
+	Time      time.Time
+	Total     int // items created by the time
+	Remaining int // items open at the time
+}
+
+// MilestoneSummary summarizes the progress of a milestone.
+type MilestoneSummary struct {
+	Milestone github.Milestone
+	Start     time.Time // creation of the milestone, or of its earliest item
+
+	Total  int
+	Open   int
+	Closed int
+
+	Burndown            []BurndownPoint
+	ClosureRate         float64   // items closed per day since the start
+	ProjectedCompletion time.Time // zero if no item has been closed
+	Baseline            time.Time // end of planning, zero if unknown
+	ScopeCreep          []github.Issue
+	Added               map[int]time.Time // time of adding items to the milestone
+}
+
+// Overdue reports whether the projected completion is later than the due date.
+func (s *MilestoneSummary) Overdue() bool {
+	if s.Milestone.DueOn == nil || s.Open == 0 {
+		return false
+	}
+	return s.ProjectedCompletion.IsZero() || s.ProjectedCompletion.After(*s.Milestone.DueOn)
+}
+
+type SummarizeMilestonesOptions struct {
+	Issues     map[int]github.Issue
+	Milestones map[string]github.Milestone    // complements milestone details missing in the issues if not nil
+	Timelines  map[int][]github.TimelineEvent // dates items added to milestones if not nil
+	Bucket     BucketSize
+	Now        time.Time
+
+	PlannedBy time.Time     // end of planning for all milestones if not zero
+	Grace     time.Duration // planning period after the creation of a milestone
+}
+
+// SummarizeMilestones summarizes the issues and pull requests in each
+// milestone, where the milestone snapshot complements the milestone details,
+// such as the creation and due dates, missing in the issues.
+// The burndown is sampled at the end of each time bucket until now or the
+// closure of the milestone.
+// Items are added to a milestone by the last milestoned events in the timelines
+// if not nil, or otherwise assumed to be added at creation. Items added after
+// the planning baseline, which is PlannedBy if set or the grace period after
+// the creation of the milestone, are reported as scope creep. The baseline is
+// unknown if neither PlannedBy nor the creation of the milestone is known.
+func SummarizeMilestones(opts SummarizeMilestonesOptions) []*MilestoneSummary {
+	now := opts.Now
+	timelines := opts.Timelines
+
+	// group items by milestone
+	members := make(map[string][]github.Issue)
+	allMilestones := make(map[string]github.Milestone)
+	for _, issue := range opts.Issues {
+		if issue.Milestone.Title == "" {
+			continue
+		}
+		members[issue.Milestone.Title] = append(members[issue.Milestone.Title], issue)
+		allMilestones[issue.Milestone.Title] = issue.Milestone
+	}
+	for title, milestone := range opts.Milestones {
+		allMilestones[title] = milestone
+	}
+
+	summaries := make([]*MilestoneSummary, 0, len(allMilestones))
+	for title, milestone := range allMilestones {
+		items := members[title]
+		summary := &MilestoneSummary{
+			Milestone: milestone,
+			Start:     milestone.CreatedAt,
+			Total:     len(items),
+			Added:     make(map[int]time.Time, len(items)),
+		}
+		for _, item := range items {
+			summary.Added[item.Number] = milestonedAt(item, timelines[item.Number])
+		}
+		slices.SortFunc(items, func(a, b github.Issue) int {
+			return summary.Added[a.Number].Compare(summary.Added[b.Number])
+		})
+		if summary.Start.IsZero() && len(items) > 0 {
+			summary.Start = summary.Added[items[0].Number]
+		}
+		switch {
+		case !opts.PlannedBy.IsZero():
+			summary.Baseline = opts.PlannedBy
+		case !milestone.CreatedAt.IsZero():
+			summary.Baseline = milestone.CreatedAt.Add(opts.Grace)
+		}
+		var lastClosedAt time.Time
+		for _, item := range items {
+			if item.State == "closed" {
+				summary.Closed++
+				if item.ClosedAt != nil && item.ClosedAt.After(lastClosedAt) {
+					lastClosedAt = *item.ClosedAt
+				}
+			} else {
+				summary.Open++
+			}
+			if !summary.Baseline.IsZero() && summary.Added[item.Number].After(summary.Baseline) {
+				summary.ScopeCreep = append(summary.ScopeCreep, item)
+			}
+		}
+
+		// burndown
+		end := now
+		if milestone.ClosedAt != nil && milestone.ClosedAt.Before(end) {
+			end = *milestone.ClosedAt
+		}
+		if !summary.Start.IsZero() {
+			timeFrame := TimeFrame{Start: summary.Start, End: end}
+			for _, b := range timeFrame.Buckets(opts.Bucket, summary.Start, end) {
+				point := BurndownPoint{Time: b.End}
+				for _, item := range items {
+					if summary.Added[item.Number].After(b.End) {
+						continue
+					}
+					point.Total++
+					if item.ClosedAt == nil || item.ClosedAt.After(b.End) {
+						point.Remaining++
+					}
+				}
+				summary.Burndown = append(summary.Burndown, point)
+			}
+		}
+
+		// projection
+		if summary.Open == 0 {
+			summary.ProjectedCompletion = lastClosedAt
+		} else if days := now.Sub(summary.Start).Hours() / 24; days > 0 && summary.Closed > 0 {
+			summary.ClosureRate = float64(summary.Closed) / days
+			remaining := float64(summary.Open) / summary.ClosureRate
+			summary.ProjectedCompletion = now.Add(time.Duration(remaining * float64(24*time.Hour)))
+		}
+		summaries = append(summaries, summary)
+	}
+	slices.SortFunc(summaries, func(a, b *MilestoneSummary) int {
+		return cmp.Compare(a.Milestone.Title, b.Milestone.Title)
+	})
+	return summaries
+}
+
+// milestonedAt returns the time of the last milestoned event adding the issue
+// to its current milestone, or the creation time if unknown.
+func milestonedAt(issue github.Issue, events []github.TimelineEvent) time.Time {
+	var added time.Time
+	for _, event := range events {
+		if event.Event != github.EventMilestoned || event.Milestone == nil || event.Milestone.Title != issue.Milestone.Title {
+			continue
+		}
+		if when := event.When(); when.After(added) {
+			added = when
+		}
+	}
+	if added.IsZero() {
+		return issue.CreatedAt
+	}
+	return added
+}
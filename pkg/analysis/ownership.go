@@ -0,0 +1,215 @@
+package analysis
+
+import (
+	"strings"
+	"time"
+
+	"github.com/shizhMSFT/gha/pkg/calendar"
+	"github.com/shizhMSFT/gha/pkg/container/set"
+	"github.com/shizhMSFT/gha/pkg/github"
+	"github.com/shizhMSFT/gha/pkg/owners"
+)
+
+// PatternCoverage is the owner review coverage of an ownership rule.
+type PatternCoverage struct {
+	Rule owners.Rule
+
+	PullRequests set.Set[int] // merged pull requests touching the pattern
+	Reviewed     set.Set[int] // reviewed by an owner of the pattern before merge
+	Approved     set.Set[int] // approved by an owner of the pattern before merge
+}
+
+// OwnerGroupLatency is the time to the first review by an owner group, which
+// is the owners of a rule.
+type OwnerGroupLatency struct {
+	Owners   string                // owners joined by spaces
+	Reviewed map[int]time.Duration // duration from creation to the first owner review by pull request
+	NoReview set.Set[int]          // merged without a review by the group
+	Patterns set.Set[string]
+	Unknown  bool // owned by teams without known members only
+}
+
+// OwnerReviewSummary summarizes the owner review coverage of the pull requests
+// merged in the time frame.
+type OwnerReviewSummary struct {
+	TimeFrame
+
+	Patterns []*PatternCoverage            // coverage by pattern in the order of the ownership file
+	Groups   map[string]*OwnerGroupLatency // time to owner review by owner group
+
+	PullRequests int          // merged pull requests with changed files
+	Covered      set.Set[int] // every owned path reviewed by an owner
+	Uncovered    set.Set[int] // some owned paths not reviewed by an owner
+	Ownerless    set.Set[int] // touching no owned paths
+	NoFiles      set.Set[int] // merged pull requests without changed files
+
+	UnownedPaths map[string]set.Set[int] // pull requests by changed path without owners
+}
+
+func NewOwnerReviewSummary() *OwnerReviewSummary {
+	return &OwnerReviewSummary{
+		Groups:       make(map[string]*OwnerGroupLatency),
+		Covered:      set.New[int](),
+		Uncovered:    set.New[int](),
+		Ownerless:    set.New[int](),
+		NoFiles:      set.New[int](),
+		UnownedPaths: make(map[string]set.Set[int]),
+	}
+}
+
+type SummarizeOwnerReviewsOptions struct {
+	TimeFrame TimeFrame
+	Issues    map[int]github.Issue
+	Reviews   map[int][]github.PullRequestReview
+	Files     map[int][]github.PullRequestFile
+	Owners    *owners.File
+	Teams     owners.Teams // resolves team owners to members if not nil
+	Calendar  *calendar.Calendar
+}
+
+// SummarizeOwnerReviews checks whether an owner of every path changed by the
+// pull requests merged in the time frame reviewed them before merge.
+// Each changed path is owned by the last matching rule of the ownership file,
+// and a rule without owners leaves its paths unowned.
+// Reviews by the author and pending reviews are ignored. Team owners count
+// only when their members are known.
+func SummarizeOwnerReviews(opts SummarizeOwnerReviewsOptions) *OwnerReviewSummary {
+	summary := NewOwnerReviewSummary()
+	summary.TimeFrame = opts.TimeFrame
+	coverages := make(map[string]*PatternCoverage) // by pattern
+	for _, rule := range opts.Owners.Rules {
+		if coverage, ok := coverages[rule.Pattern]; ok {
+			// the last rule of the same pattern takes precedence
+			coverage.Rule = rule
+			continue
+		}
+		coverage := &PatternCoverage{
+			Rule:         rule,
+			PullRequests: set.New[int](),
+			Reviewed:     set.New[int](),
+			Approved:     set.New[int](),
+		}
+		summary.Patterns = append(summary.Patterns, coverage)
+		coverages[rule.Pattern] = coverage
+	}
+
+	for number, issue := range opts.Issues {
+		if !issue.Merged() || !opts.TimeFrame.Contains(*issue.PullRequest.MergedAt) {
+			continue
+		}
+		files := opts.Files[number]
+		if len(files) == 0 {
+			summary.NoFiles.Add(number)
+			continue
+		}
+		summary.PullRequests++
+		mergedAt := *issue.PullRequest.MergedAt
+
+		// first review and approval times by reviewer before merge
+		reviewedAt := make(map[string]time.Time)
+		approved := set.New[string]()
+		for _, review := range opts.Reviews[number] {
+			if review.State == "" || review.State == github.ReviewStatePending {
+				continue
+			}
+			if review.SubmittedAt.IsZero() || review.SubmittedAt.After(mergedAt) {
+				continue
+			}
+			reviewer := strings.ToLower(review.User.Login)
+			if strings.EqualFold(reviewer, issue.User.Login) {
+				continue
+			}
+			if first, ok := reviewedAt[reviewer]; !ok || review.SubmittedAt.Before(first) {
+				reviewedAt[reviewer] = review.SubmittedAt
+			}
+			if review.State == github.ReviewStateApproved {
+				approved.Add(reviewer)
+			}
+		}
+
+		// match changed files to rules
+		touched := make(map[string]owners.Rule) // rules by pattern
+		for _, file := range files {
+			rule, ok := opts.Owners.Match(file.Filename)
+			if !ok || len(rule.Owners) == 0 {
+				if summary.UnownedPaths[file.Filename] == nil {
+					summary.UnownedPaths[file.Filename] = set.New[int]()
+				}
+				summary.UnownedPaths[file.Filename].Add(number)
+				continue
+			}
+			touched[rule.Pattern] = rule
+		}
+		if len(touched) == 0 {
+			summary.Ownerless.Add(number)
+			continue
+		}
+
+		covered := true
+		for pattern, rule := range touched {
+			coverage, ok := coverages[pattern]
+			if !ok {
+				// combined rules of additive ownership files
+				coverage = &PatternCoverage{
+					Rule:         rule,
+					PullRequests: set.New[int](),
+					Reviewed:     set.New[int](),
+					Approved:     set.New[int](),
+				}
+				summary.Patterns = append(summary.Patterns, coverage)
+				coverages[pattern] = coverage
+			}
+			rule = coverage.Rule
+			coverage.PullRequests.Add(number)
+			group := summary.group(rule, opts.Teams)
+			group.Patterns.Add(rule.Pattern)
+
+			var firstReview time.Time
+			for _, login := range opts.Teams.Logins(rule.Owners) {
+				if approved.Contains(login) {
+					coverage.Approved.Add(number)
+				}
+				if t, ok := reviewedAt[login]; ok && (firstReview.IsZero() || t.Before(firstReview)) {
+					firstReview = t
+				}
+			}
+			if firstReview.IsZero() {
+				covered = false
+				group.NoReview.Add(number)
+				continue
+			}
+			coverage.Reviewed.Add(number)
+			duration := opts.Calendar.Duration(issue.CreatedAt, firstReview)
+			if prev, ok := group.Reviewed[number]; !ok || duration < prev {
+				group.Reviewed[number] = duration
+			}
+		}
+		if covered {
+			summary.Covered.Add(number)
+		} else {
+			summary.Uncovered.Add(number)
+		}
+	}
+	return summary
+}
+
+// group returns the owner group of the rule.
+func (s *OwnerReviewSummary) group(rule owners.Rule, teams owners.Teams) *OwnerGroupLatency {
+	names := make([]string, 0, len(rule.Owners))
+	for _, owner := range rule.Owners {
+		names = append(names, owner.String())
+	}
+	key := strings.Join(names, " ")
+	group, ok := s.Groups[key]
+	if !ok {
+		group = &OwnerGroupLatency{
+			Owners:   key,
+			Reviewed: make(map[int]time.Duration),
+			NoReview: set.New[int](),
+			Patterns: set.New[string](),
+			Unknown:  len(teams.Logins(rule.Owners)) == 0,
+		}
+		s.Groups[key] = group
+	}
+	return group
+}
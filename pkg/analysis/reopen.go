@@ -0,0 +1,127 @@
+package analysis
+
+import (
+	"slices"
+	"time"
+
+	"github.com/shizhMSFT/gha/pkg/github"
+)
+
+type ReopenSummary struct {
+	TimeFrame
+
+	Closed       map[int]int             // number of closures by item
+	Reopened     map[int]int             // number of reopens by item
+	TimeToReopen map[int][]time.Duration // durations from closure to reopen by item, if known
+}
+
+func NewReopenSummary() *ReopenSummary {
+	return &ReopenSummary{
+		Closed:       make(map[int]int),
+		Reopened:     make(map[int]int),
+		TimeToReopen: make(map[int][]time.Duration),
+	}
+}
+
+// Rate returns the ratio of closed items being reopened.
+func (s *ReopenSummary) Rate() float64 {
+	if len(s.Closed) == 0 {
+		return 0
+	}
+	return float64(len(s.Reopened)) / float64(len(s.Closed))
+}
+
+// Durations returns all the durations from closure to reopen.
+func (s *ReopenSummary) Durations() []time.Duration {
+	var durations []time.Duration
+	for _, d := range s.TimeToReopen {
+		durations = append(durations, d...)
+	}
+	return durations
+}
+
+// Churned returns the items reopened at least n times, sorted by the number
+// of reopens in descending order.
+func (s *ReopenSummary) Churned(n int) []int {
+	var numbers []int
+	for number, count := range s.Reopened {
+		if count >= n {
+			numbers = append(numbers, number)
+		}
+	}
+	slices.SortFunc(numbers, func(a, b int) int {
+		if c := s.Reopened[b] - s.Reopened[a]; c != 0 {
+			return c
+		}
+		return a - b
+	})
+	return numbers
+}
+
+// SummarizeReopens summarizes the closures and reopens of the issues and pull
+// requests created in the time frame, using their timelines.
+func SummarizeReopens(issues map[int]github.Issue, timelines map[int][]github.TimelineEvent, timeFrame TimeFrame) *ReopenSummary {
+	summary := NewReopenSummary()
+	summary.TimeFrame = timeFrame
+	for number, issue := range issues {
+		if !timeFrame.Contains(issue.CreatedAt) {
+			continue
+		}
+		events := sortEvents(timelines[number])
+		var closedAt time.Time
+		for _, event := range events {
+			switch event.Event {
+			case github.EventClosed:
+				summary.Closed[number]++
+				closedAt = event.When()
+			case github.EventReopened:
+				summary.Reopened[number]++
+				if !closedAt.IsZero() {
+					summary.TimeToReopen[number] = append(summary.TimeToReopen[number], event.When().Sub(closedAt))
+					closedAt = time.Time{}
+				}
+			}
+		}
+	}
+	return summary
+}
+
+// SummarizeReopenSnapshots summarizes the closures and reopens of the issues
+// and pull requests created in the time frame, using a series of snapshots in
+// chronological order.
+// An item is reopened if it is closed in a snapshot and open in the next one,
+// or if it is closed again at a later time. Closures and reopens between two
+// snapshots are missed.
+// The time of reopen is approximated by the last update in the next snapshot,
+// which is an upper bound.
+func SummarizeReopenSnapshots(snapshots []map[int]github.Issue, timeFrame TimeFrame) *ReopenSummary {
+	summary := NewReopenSummary()
+	summary.TimeFrame = timeFrame
+	prev := make(map[int]github.Issue)
+	for _, snapshot := range snapshots {
+		for number, issue := range snapshot {
+			if !timeFrame.Contains(issue.CreatedAt) {
+				continue
+			}
+			last, seen := prev[number]
+			prev[number] = issue
+			wasClosed := seen && last.State == "closed" && last.ClosedAt != nil
+			switch {
+			case issue.State == "closed" && issue.ClosedAt != nil:
+				if !wasClosed {
+					summary.Closed[number]++
+				} else if issue.ClosedAt.After(*last.ClosedAt) {
+					// reopened and closed again between snapshots
+					summary.Closed[number]++
+					summary.Reopened[number]++
+				}
+			case wasClosed:
+				summary.Reopened[number]++
+				if issue.UpdatedAt.After(*last.ClosedAt) {
+					summary.TimeToReopen[number] = append(summary.TimeToReopen[number], issue.UpdatedAt.Sub(*last.ClosedAt))
+				}
+			}
+		}
+	}
+	return summary
+}
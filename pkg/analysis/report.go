@@ -3,14 +3,17 @@ package analysis
 import (
 	"time"
 
+	"github.com/shizhMSFT/gha/pkg/calendar"
 	"github.com/shizhMSFT/gha/pkg/github"
+	"github.com/shizhMSFT/gha/pkg/identity"
 )
 
 type IssueSummary struct {
-	Total     int
-	Open      int
-	Closed    int
-	Durations []time.Duration // time to close
+	Total         int
+	Open          int
+	Closed        int
+	Durations     []time.Duration // time to close
+	OpenDurations []time.Duration // age of open issues, censored time to close
 }
 
 func (s *IssueSummary) Union(other *IssueSummary) {
@@ -18,14 +21,17 @@ func (s *IssueSummary) Union(other *IssueSummary) {
 	s.Open += other.Open
 	s.Closed += other.Closed
 	s.Durations = append(s.Durations, other.Durations...)
+	s.OpenDurations = append(s.OpenDurations, other.OpenDurations...)
 }
 
 type PullRequestSummary struct {
-	Total     int
-	Open      int
-	Closed    int
-	Merged    int
-	Durations []time.Duration // time to merge
+	Total           int
+	Open            int
+	Closed          int
+	Merged          int
+	Durations       []time.Duration // time to merge
+	OpenDurations   []time.Duration // age of open pull requests, censored time to merge
+	ClosedDurations []time.Duration // time to close without merge, censored time to merge
 }
 
 func (s *PullRequestSummary) Union(other *PullRequestSummary) {
@@ -34,6 +40,8 @@ func (s *PullRequestSummary) Union(other *PullRequestSummary) {
 	s.Closed += other.Closed
 	s.Merged += other.Merged
 	s.Durations = append(s.Durations, other.Durations...)
+	s.OpenDurations = append(s.OpenDurations, other.OpenDurations...)
+	s.ClosedDurations = append(s.ClosedDurations, other.ClosedDurations...)
 }
 
 type RepositorySummary struct {
@@ -67,49 +75,68 @@ func NewSummary() *Summary {
 	}
 }
 
-func Summarize(issues map[int]github.Issue, timeFrame TimeFrame) *Summary {
+// Summarize summarizes the issues and pull requests created in the time frame,
+// where authors are resolved to people by the identities, and durations are
+// measured in the working time of the calendar.
+// Items are counted in their state at the end of the time frame, so items
+// closed after the end are counted as open. The ages of open items are measured
+// until the end of the time frame, or now if the time frame is open-ended.
+func Summarize(issues map[int]github.Issue, timeFrame TimeFrame, identities *identity.Map, cal *calendar.Calendar) *Summary {
 	summary := NewSummary()
 	summary.TimeFrame = timeFrame
 	for _, issue := range issues {
 		if !timeFrame.Contains(issue.CreatedAt) {
 			continue
 		}
-		author := issue.User.Login
+		author := identities.Person(issue.User.Login)
 		authorSummary := summary.Authors[author]
 		if authorSummary == nil {
 			authorSummary = NewRepositorySummary()
 			summary.Authors[author] = authorSummary
 		}
+		state := issue.State
+		if closedAfter(issue, timeFrame.End) {
+			state = "open"
+		}
 		if issue.IsPullRequest() {
 			summary.PullRequest.Total++
 			authorSummary.PullRequest.Total++
-			switch issue.State {
+			switch state {
 			case "open":
 				summary.PullRequest.Open++
 				authorSummary.PullRequest.Open++
+				duration := openAge(issue, timeFrame, cal)
+				summary.PullRequest.OpenDurations = append(summary.PullRequest.OpenDurations, duration)
+				authorSummary.PullRequest.OpenDurations = append(authorSummary.PullRequest.OpenDurations, duration)
 			case "closed":
 				if issue.Merged() {
 					summary.PullRequest.Merged++
 					authorSummary.PullRequest.Merged++
-					duration := issue.Duration()
+					duration := IssueDuration(issue, cal)
 					summary.PullRequest.Durations = append(summary.PullRequest.Durations, duration)
 					authorSummary.PullRequest.Durations = append(authorSummary.PullRequest.Durations, duration)
 				} else {
 					summary.PullRequest.Closed++
 					authorSummary.PullRequest.Closed++
+					duration := IssueDuration(issue, cal)
+					summary.PullRequest.ClosedDurations = append(summary.PullRequest.ClosedDurations, duration)
+					authorSummary.PullRequest.ClosedDurations = append(authorSummary.PullRequest.ClosedDurations, duration)
 				}
 			}
 		} else {
 			summary.Issue.Total++
 			authorSummary.Issue.Total++
-			switch issue.State {
+			switch state {
 			case "open":
 				summary.Issue.Open++
 				authorSummary.Issue.Open++
+				duration := openAge(issue, timeFrame, cal)
+				summary.Issue.OpenDurations = append(summary.Issue.OpenDurations, duration)
+				authorSummary.Issue.OpenDurations = append(authorSummary.Issue.OpenDurations, duration)
 			case "closed":
 				summary.Issue.Closed++
 				authorSummary.Issue.Closed++
-				duration := issue.Duration()
+				duration := IssueDuration(issue, cal)
 				summary.Issue.Durations = append(summary.Issue.Durations, duration)
 				authorSummary.Issue.Durations = append(authorSummary.Issue.Durations, duration)
 			}
@@ -118,6 +145,20 @@ func Summarize(issues map[int]github.Issue, timeFrame TimeFrame) *Summary {
 	return summary
 }
 
+// closedAfter reports whether the issue was closed after end, if end is set.
+func closedAfter(issue github.Issue, end time.Time) bool {
+	return !end.IsZero() && issue.ClosedAt != nil && issue.ClosedAt.After(end)
+}
+
+// openAge returns the age of the open issue at the end of the time frame, or
+// now if the time frame is open-ended.
+func openAge(issue github.Issue, timeFrame TimeFrame, cal *calendar.Calendar) time.Duration {
+	if timeFrame.End.IsZero() {
+		return cal.Since(issue.CreatedAt)
+	}
+	return cal.Duration(issue.CreatedAt, timeFrame.End)
+}
+
 func (s *Summary) Union(other *Summary) {
 	s.RepositorySummary.Union(other.RepositorySummary)
 	s.TimeFrame.Union(other.TimeFrame)
@@ -135,7 +176,9 @@ func (s *Summary) Union(other *Summary) {
 type Report struct {
 	TimeFrame
 
-	Summaries map[string]*Summary
+	Summaries  map[string]*Summary
+	Identities *identity.Map // resolves authors to people if not nil
+	Calendar   *calendar.Calendar
 }
 
 func NewReport(timeFrame TimeFrame) *Report {
@@ -146,7 +189,7 @@ func NewReport(timeFrame TimeFrame) *Report {
 }
 
 func (r *Report) Summarize(name string, issues map[int]github.Issue) *Summary {
-	summary := Summarize(issues, r.TimeFrame)
+	summary := Summarize(issues, r.TimeFrame, r.Identities, r.Calendar)
 	r.Summaries[name] = summary
 	return summary
 }
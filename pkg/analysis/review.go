@@ -1,19 +1,28 @@
 package analysis
 
 import (
+	"slices"
+	"time"
+
+	"github.com/shizhMSFT/gha/pkg/actor"
+	"github.com/shizhMSFT/gha/pkg/calendar"
 	"github.com/shizhMSFT/gha/pkg/container/set"
 	"github.com/shizhMSFT/gha/pkg/github"
+	"github.com/shizhMSFT/gha/pkg/identity"
+	"github.com/shizhMSFT/gha/pkg/sort"
 )
 
 type PullRequestReviewSummary struct {
 	TimeFrame
 
 	Reviewers map[string]set.Set[int]
+	States    map[string]map[string]int // review counts by reviewer and state
 }
 
 func NewPullRequestReviewSummary() *PullRequestReviewSummary {
 	return &PullRequestReviewSummary{
 		Reviewers: make(map[string]set.Set[int]),
+		States:    make(map[string]map[string]int),
 	}
 }
 
@@ -25,41 +34,226 @@ func (s *PullRequestReviewSummary) ReviewCount() map[string]int {
 	return counts
 }
 
-func SummarizePullRequestReviews(reviews map[int][]github.PullRequestReview, timeFrame TimeFrame) *PullRequestReviewSummary {
+// SummarizePullRequestReviews summarizes the reviews submitted in the time
+// frame, where reviewers are resolved to people by the identities.
+// Reviews by the authors of the pull requests in issues, including their other
+// accounts in the identities, are ignored, such as replies to review threads.
+func SummarizePullRequestReviews(issues map[int]github.Issue, reviews map[int][]github.PullRequestReview, timeFrame TimeFrame, identities *identity.Map) *PullRequestReviewSummary {
 	summary := NewPullRequestReviewSummary()
 	summary.TimeFrame = timeFrame
 	for number, reviews := range reviews {
+		issue, known := issues[number]
 		for _, review := range reviews {
 			if !timeFrame.Contains(review.SubmittedAt) {
 				continue
 			}
-			reviewer := review.User.Login
+			if known && identities.Same(review.User.Login, issue.User.Login) {
+				continue
+			}
+			reviewer := identities.Person(review.User.Login)
 			numbers := summary.Reviewers[reviewer]
 			if numbers == nil {
 				numbers = set.New[int]()
 				summary.Reviewers[reviewer] = numbers
 			}
 			numbers.Add(number)
+			if review.State == "" || review.State == github.ReviewStatePending {
+				continue
+			}
+			states := summary.States[reviewer]
+			if states == nil {
+				states = make(map[string]int)
+				summary.States[reviewer] = states
+			}
+			states[review.State]++
+		}
+	}
+	return summary
+}
+
+type MergeReviewSummary struct {
+	TimeFrame
+
+	Rounds          map[int]int           // review rounds before merge
+	ApprovalToMerge map[int]time.Duration // duration from the last approval to merge
+}
+
+func NewMergeReviewSummary() *MergeReviewSummary {
+	return &MergeReviewSummary{
+		Rounds:          make(map[int]int),
+		ApprovalToMerge: make(map[int]time.Duration),
+	}
+}
+
+type SummarizeMergeReviewsOptions struct {
+	TimeFrame  TimeFrame
+	Issues     map[int]github.Issue
+	Reviews    map[int][]github.PullRequestReview
+	Calendar   *calendar.Calendar
+	Bots       *actor.Classifier // recognizes bots by login patterns if not nil
+	Identities *identity.Map     // resolves authors and reviewers to people if not nil
+}
+
+// SummarizeMergeReviews summarizes the reviews submitted before merge for the
+// pull requests created in the time frame and merged.
+// A review round ends with change requests on a revision, so a pull request
+// merged without any change request takes one round. Change requests on the
+// same commit count as one round, or, if the commits are unknown, change
+// requests until the next review of another state. Dismissed reviews only show
+// their state after dismissal, so change requests later dismissed are excluded
+// from the rounds. Pull requests merged without review are not counted. Self-reviews, including reviews by other
+// accounts of the author in the identities, and reviews by bots are ignored.
+// Durations are measured in the working time of the calendar.
+func SummarizeMergeReviews(opts SummarizeMergeReviewsOptions) *MergeReviewSummary {
+	summary := NewMergeReviewSummary()
+	summary.TimeFrame = opts.TimeFrame
+	for number, issue := range opts.Issues {
+		if !issue.Merged() {
+			continue
+		}
+		if !opts.TimeFrame.Contains(issue.CreatedAt) {
+			continue
+		}
+		mergedAt := *issue.PullRequest.MergedAt
+		reviewed := false
+		rounds := 1
+		var approvedAt time.Time
+		changing := false       // whether the current round has change requests
+		var changeCommit string // commit of the last change request
+		for _, review := range sortReviews(opts.Reviews[number]) {
+			if review.SubmittedAt.IsZero() || review.SubmittedAt.After(mergedAt) {
+				continue
+			}
+			if opts.Bots.IsBot(review.User) || opts.Identities.Same(review.User.Login, issue.User.Login) {
+				continue
+			}
+			reviewed = true
+			if review.State == github.ReviewStateDismissed {
+				continue
+			}
+			if review.State != github.ReviewStateChangesRequested && (review.CommitID == "" || changeCommit == "") {
+				changing = false
+			}
+			switch review.State {
+			case github.ReviewStateChangesRequested:
+				if !changing || (review.CommitID != "" && review.CommitID != changeCommit) {
+					rounds++
+				}
+				changing = true
+				changeCommit = review.CommitID
+			case github.ReviewStateApproved:
+				if review.SubmittedAt.After(approvedAt) {
+					approvedAt = review.SubmittedAt
+				}
+			}
+		}
+		if !reviewed {
+			continue
+		}
+		summary.Rounds[number] = rounds
+		if !approvedAt.IsZero() {
+			summary.ApprovalToMerge[number] = opts.Calendar.Duration(approvedAt, mergedAt)
+		}
+	}
+	return summary
+}
+
+type FirstReviewSummary struct {
+	TimeFrame
+
+	Reviewed            map[int]time.Duration // duration of first review
+	NoReview            map[int]time.Time     // time of open pull request creation
+	ClosedWithoutReview map[int]time.Duration // duration until closed without review
+}
+
+func NewFirstReviewSummary() *FirstReviewSummary {
+	return &FirstReviewSummary{
+		Reviewed:            make(map[int]time.Duration),
+		NoReview:            make(map[int]time.Time),
+		ClosedWithoutReview: make(map[int]time.Duration),
+	}
+}
+
+type SummarizeFirstReviewsOptions struct {
+	TimeFrame  TimeFrame
+	Issues     map[int]github.Issue
+	Reviews    map[int][]github.PullRequestReview
+	Calendar   *calendar.Calendar
+	Bots       *actor.Classifier // recognizes bots by login patterns if not nil
+	Identities *identity.Map     // resolves authors and reviewers to people if not nil
+}
+
+// SummarizeFirstReviews measures how long pull requests created in the time
+// frame wait for their first review.
+// Self-reviews, including reviews by other accounts of the author in the
+// identities, and reviews by bots are not counted.
+// Durations are measured in the working time of the calendar.
+func SummarizeFirstReviews(opts SummarizeFirstReviewsOptions) *FirstReviewSummary {
+	cal := opts.Calendar
+	summary := NewFirstReviewSummary()
+	summary.TimeFrame = opts.TimeFrame
+	for number, issue := range opts.Issues {
+		if !issue.IsPullRequest() {
+			continue
+		}
+		if !opts.TimeFrame.Contains(issue.CreatedAt) {
+			continue
+		}
+		var first time.Time
+		for _, review := range opts.Reviews[number] {
+			if review.SubmittedAt.IsZero() {
+				continue // pending review
+			}
+			if opts.Bots.IsBot(review.User) || opts.Identities.Same(review.User.Login, issue.User.Login) {
+				continue
+			}
+			if first.IsZero() || review.SubmittedAt.Before(first) {
+				first = review.SubmittedAt
+			}
+		}
+		switch {
+		case !first.IsZero():
+			summary.Reviewed[number] = cal.Duration(issue.CreatedAt, first)
+		case issue.State == "closed":
+			summary.ClosedWithoutReview[number] = IssueDuration(issue, cal)
+		default:
+			summary.NoReview[number] = issue.CreatedAt
 		}
 	}
 	return summary
 }
 
+// OutOfSLA returns the pull requests waiting for the first review longer than
+// sla, including open pull requests not reviewed yet, where the waiting time
+// is measured in the working time of cal.
+func (s *FirstReviewSummary) OutOfSLA(sla time.Duration, now time.Time, cal *calendar.Calendar) sort.MapEntrySlice[int, time.Duration] {
+	return outOfSLA(s.Reviewed, s.NoReview, sla, now, cal)
+}
+
 type PullRequestReviewReport struct {
 	TimeFrame
 
-	Summaries map[string]*PullRequestReviewSummary
+	Summaries    map[string]*PullRequestReviewSummary
+	FirstReviews map[string]*FirstReviewSummary
+	Merges       map[string]*MergeReviewSummary
+	Matrices     map[string]*ReviewMatrix
+	Identities   *identity.Map // resolves authors and reviewers to people if not nil
+	Calendar     *calendar.Calendar
+	Bots         *actor.Classifier // recognizes bots by login patterns if not nil
 }
 
 func NewPullRequestReviewReport(timeFrame TimeFrame) *PullRequestReviewReport {
 	return &PullRequestReviewReport{
-		TimeFrame: timeFrame,
-		Summaries: make(map[string]*PullRequestReviewSummary),
+		TimeFrame:    timeFrame,
+		Summaries:    make(map[string]*PullRequestReviewSummary),
+		FirstReviews: make(map[string]*FirstReviewSummary),
+		Merges:       make(map[string]*MergeReviewSummary),
+		Matrices:     make(map[string]*ReviewMatrix),
 	}
 }
 
-func (r *PullRequestReviewReport) Summarize(name string, reviews map[int][]github.PullRequestReview) *PullRequestReviewSummary {
-	summary := SummarizePullRequestReviews(reviews, r.TimeFrame)
+func (r *PullRequestReviewReport) Summarize(name string, issues map[int]github.Issue, reviews map[int][]github.PullRequestReview) *PullRequestReviewSummary {
+	summary := SummarizePullRequestReviews(issues, reviews, r.TimeFrame, r.Identities)
 	r.Summaries[name] = summary
 	return summary
 }
@@ -73,3 +267,69 @@ func (r *PullRequestReviewReport) ReviewCount() map[string]int {
 	}
 	return counts
 }
+
+func (r *PullRequestReviewReport) SummarizeFirstReviews(name string, issues map[int]github.Issue, reviews map[int][]github.PullRequestReview) *FirstReviewSummary {
+	summary := SummarizeFirstReviews(SummarizeFirstReviewsOptions{
+		TimeFrame:  r.TimeFrame,
+		Issues:     issues,
+		Reviews:    reviews,
+		Calendar:   r.Calendar,
+		Bots:       r.Bots,
+		Identities: r.Identities,
+	})
+	r.FirstReviews[name] = summary
+	return summary
+}
+
+func (r *PullRequestReviewReport) SummarizeMergeReviews(name string, issues map[int]github.Issue, reviews map[int][]github.PullRequestReview) *MergeReviewSummary {
+	summary := SummarizeMergeReviews(SummarizeMergeReviewsOptions{
+		TimeFrame:  r.TimeFrame,
+		Issues:     issues,
+		Reviews:    reviews,
+		Calendar:   r.Calendar,
+		Bots:       r.Bots,
+		Identities: r.Identities,
+	})
+	r.Merges[name] = summary
+	return summary
+}
+
+func (r *PullRequestReviewReport) StateCount() map[string]map[string]int {
+	counts := make(map[string]map[string]int)
+	for _, summary := range r.Summaries {
+		for reviewer, states := range summary.States {
+			reviewerCounts := counts[reviewer]
+			if reviewerCounts == nil {
+				reviewerCounts = make(map[string]int)
+				counts[reviewer] = reviewerCounts
+			}
+			for state, count := range states {
+				reviewerCounts[state] += count
+			}
+		}
+	}
+	return counts
+}
+
+func (r *PullRequestReviewReport) SummarizeReviewMatrix(name string, issues map[int]github.Issue, reviews map[int][]github.PullRequestReview) *ReviewMatrix {
+	matrix := SummarizeReviewMatrix(issues, reviews, r.TimeFrame, r.Identities, r.Bots)
+	r.Matrices[name] = matrix
+	return matrix
+}
+
+func (r *PullRequestReviewReport) ReviewMatrix() *ReviewMatrix {
+	matrix := NewReviewMatrix()
+	for _, other := range r.Matrices {
+		matrix.Union(other)
+	}
+	return matrix
+}
+
+// sortReviews returns a copy of the reviews sorted by submission time.
+func sortReviews(reviews []github.PullRequestReview) []github.PullRequestReview {
+	sorted := slices.Clone(reviews)
+	slices.SortStableFunc(sorted, func(a, b github.PullRequestReview) int {
+		return a.SubmittedAt.Compare(b.SubmittedAt)
+	})
+	return sorted
+}
@@ -0,0 +1,72 @@
+package analysis
+
+import (
+	"slices"
+	"time"
+
+	"github.com/shizhMSFT/gha/pkg/actor"
+	"github.com/shizhMSFT/gha/pkg/github"
+)
+
+// StaleItem is an open issue or pull request without recent activity.
+type StaleItem struct {
+	Issue        github.Issue
+	LastActivity time.Time
+	Idle         time.Duration
+}
+
+// FindStale returns the open issues and pull requests without activity for
+// longer than idle, sorted from the most stale.
+// If the comments of an item are in comments, or the item has no comments, the
+// last activity is the latest of its creation and its comments by humans, as
+// well as its last update for pull requests since commits and reviews are not
+// comments. Otherwise, the last activity falls back to the last update of the
+// item, which may be made by bots. Bots are recognized by the classifier.
+func FindStale(issues map[int]github.Issue, comments map[int][]github.IssueComment, idle time.Duration, now time.Time, bots *actor.Classifier) []StaleItem {
+	var items []StaleItem
+	for number, issue := range issues {
+		if issue.State != "open" {
+			continue
+		}
+		itemComments, ok := comments[number]
+		known := comments != nil && (ok || issue.Comments == 0)
+		lastActivity := LastActivity(issue, itemComments, known, bots)
+		if d := now.Sub(lastActivity); d > idle {
+			items = append(items, StaleItem{
+				Issue:        issue,
+				LastActivity: lastActivity,
+				Idle:         d,
+			})
+		}
+	}
+	slices.SortFunc(items, func(a, b StaleItem) int {
+		if c := a.LastActivity.Compare(b.LastActivity); c != 0 {
+			return c
+		}
+		return a.Issue.Number - b.Issue.Number
+	})
+	return items
+}
+
+// LastActivity returns the time of the last human activity on the issue.
+// If the comments are not known, the last update of the issue is returned.
+// The last update of a pull request always counts as its commits and reviews
+// are not in the comments.
+func LastActivity(issue github.Issue, comments []github.IssueComment, known bool, bots *actor.Classifier) time.Time {
+	lastActivity := issue.CreatedAt
+	if (!known || issue.IsPullRequest()) && issue.UpdatedAt.After(lastActivity) {
+		lastActivity = issue.UpdatedAt
+	}
+	if !known {
+		return lastActivity
+	}
+	for _, comment := range comments {
+		if bots.IsBot(comment.User) {
+			continue
+		}
+		if comment.CreatedAt.After(lastActivity) {
+			lastActivity = comment.CreatedAt
+		}
+	}
+	return lastActivity
+}
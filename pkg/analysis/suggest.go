@@ -0,0 +1,187 @@
+package analysis
+
+import (
+	"cmp"
+	"math"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shizhMSFT/gha/pkg/actor"
+	"github.com/shizhMSFT/gha/pkg/container/set"
+	"github.com/shizhMSFT/gha/pkg/github"
+	"github.com/shizhMSFT/gha/pkg/identity"
+)
+
+// Weights of the reviewer suggestion score components.
+const (
+	InteractionWeight = 0.4
+	FamiliarityWeight = 0.3
+	RecencyWeight     = 0.3
+	LoadWeight        = 0.3
+)
+
+// RecencyHalfLife is the time for the recency score to halve since the last
+// review of a reviewer.
+const RecencyHalfLife = 30 * 24 * time.Hour
+
+// ReviewerSuggestion is a ranked reviewer candidate with the score breakdown.
+type ReviewerSuggestion struct {
+	Reviewer string
+	Score    float64
+
+	Interaction   float64 // share of the author's pull requests reviewed
+	Reviewed      int     // number of the author's pull requests reviewed
+	AuthorTotal   int     // number of the author's pull requests
+	Familiarity   float64 // share of changed files in familiar directories
+	FamiliarFiles int     // number of changed files in familiar directories
+	TotalFiles    int     // number of changed files
+	Recency       float64 // decayed by the time since the last review
+	LastReview    time.Time
+	Load          float64 // open pull requests under review relative to the busiest candidate
+	OpenReviews   int     // number of open pull requests under review
+}
+
+type SuggestReviewersOptions struct {
+	Issues  map[int]github.Issue
+	Reviews map[int][]github.PullRequestReview
+	Files   map[int][]github.PullRequestFile // optional changed files of pull requests
+
+	PullRequest int    // pull request to suggest reviewers for, if non-zero
+	Author      string // author to suggest reviewers for if PullRequest is zero
+
+	Candidates set.Set[string]   // optional candidates, matched case-insensitively
+	Identities *identity.Map     // resolves authors and reviewers to people if not nil
+	Bots       *actor.Classifier // recognizes bots by login patterns if not nil
+	Now        time.Time
+}
+
+// SuggestReviewers ranks the candidate reviewers by past interaction with the
+// author, familiarity with the directories of the changed files, recency of
+// activity, and current review load.
+// Reviewers are resolved to people by the identities. Candidates are the given
+// candidates, including those without any review, or the past reviewers if not
+// given, other than the author and bots.
+// Familiarity is only scored when changed files of the pull request are known.
+func SuggestReviewers(opts SuggestReviewersOptions) []ReviewerSuggestion {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	author := opts.Author
+	var changedDirs []string
+	if opts.PullRequest != 0 {
+		author = opts.Issues[opts.PullRequest].User.Login
+		for _, file := range opts.Files[opts.PullRequest] {
+			changedDirs = append(changedDirs, path.Dir(file.Filename))
+		}
+	}
+
+	// seed the candidates so that those without reviews are suggested as well
+	suggestions := make(map[string]*ReviewerSuggestion) // by lower-case person
+	for candidate := range opts.Candidates {
+		if opts.Identities.Same(candidate, author) {
+			continue
+		}
+		person := opts.Identities.Person(candidate)
+		suggestions[strings.ToLower(person)] = &ReviewerSuggestion{Reviewer: person}
+	}
+
+	// collect review history excluding the target pull request
+	familiarDirs := make(map[string]set.Set[string])
+	authorTotal := 0
+	for number, issue := range opts.Issues {
+		if !issue.IsPullRequest() || number == opts.PullRequest {
+			continue
+		}
+		isAuthor := opts.Identities.Same(issue.User.Login, author)
+		if isAuthor {
+			authorTotal++
+		}
+		reviewed := set.New[string]()
+		for _, review := range opts.Reviews[number] {
+			if review.SubmittedAt.IsZero() || opts.Bots.IsBot(review.User) {
+				continue
+			}
+			if opts.Identities.Same(review.User.Login, author) || opts.Identities.Same(review.User.Login, issue.User.Login) {
+				continue
+			}
+			person := opts.Identities.Person(review.User.Login)
+			reviewer := strings.ToLower(person)
+			suggestion := suggestions[reviewer]
+			if suggestion == nil {
+				if opts.Candidates.Len() > 0 {
+					continue
+				}
+				suggestion = &ReviewerSuggestion{Reviewer: person}
+				suggestions[reviewer] = suggestion
+			}
+			if review.SubmittedAt.After(suggestion.LastReview) {
+				suggestion.LastReview = review.SubmittedAt
+			}
+			if reviewed.Contains(reviewer) {
+				continue
+			}
+			reviewed.Add(reviewer)
+			if isAuthor {
+				suggestion.Reviewed++
+			}
+			if issue.State == "open" {
+				suggestion.OpenReviews++
+			}
+			dirs := familiarDirs[reviewer]
+			if dirs == nil {
+				dirs = set.New[string]()
+				familiarDirs[reviewer] = dirs
+			}
+			for _, file := range opts.Files[number] {
+				dirs.Add(path.Dir(file.Filename))
+			}
+		}
+	}
+
+	// score candidates
+	maxLoad := 0
+	for _, suggestion := range suggestions {
+		maxLoad = max(maxLoad, suggestion.OpenReviews)
+	}
+	result := make([]ReviewerSuggestion, 0, len(suggestions))
+	for reviewer, suggestion := range suggestions {
+		suggestion.AuthorTotal = authorTotal
+		if authorTotal > 0 {
+			suggestion.Interaction = float64(suggestion.Reviewed) / float64(authorTotal)
+		}
+		suggestion.TotalFiles = len(changedDirs)
+		for _, dir := range changedDirs {
+			if familiarDirs[reviewer].Contains(dir) {
+				suggestion.FamiliarFiles++
+			}
+		}
+		if suggestion.TotalFiles > 0 {
+			suggestion.Familiarity = float64(suggestion.FamiliarFiles) / float64(suggestion.TotalFiles)
+		}
+		if !suggestion.LastReview.IsZero() {
+			suggestion.Recency = math.Pow(0.5, float64(now.Sub(suggestion.LastReview))/float64(RecencyHalfLife))
+		}
+		if maxLoad > 0 {
+			suggestion.Load = float64(suggestion.OpenReviews) / float64(maxLoad)
+		}
+
+		score := InteractionWeight*suggestion.Interaction + RecencyWeight*suggestion.Recency
+		weight := InteractionWeight + RecencyWeight
+		if suggestion.TotalFiles > 0 {
+			score += FamiliarityWeight * suggestion.Familiarity
+			weight += FamiliarityWeight
+		}
+		suggestion.Score = score/weight - LoadWeight*suggestion.Load
+		result = append(result, *suggestion)
+	}
+	slices.SortFunc(result, func(a, b ReviewerSuggestion) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Reviewer, b.Reviewer)
+	})
+	return result
+}
@@ -0,0 +1,70 @@
+package analysis
+
+import (
+	"time"
+
+	"github.com/shizhMSFT/gha/pkg/calendar"
+	"github.com/shizhMSFT/gha/pkg/github"
+)
+
+// ThroughputSummary counts the issues and pull requests opened and closed in a
+// time frame.
+type ThroughputSummary struct {
+	TimeFrame
+
+	IssuesOpened         int
+	IssuesClosed         int
+	PullRequestsOpened   int
+	PullRequestsMerged   int
+	PullRequestsClosed   int             // closed without merge
+	IssueDurations       []time.Duration // time to close of issues closed in the time frame
+	PullRequestDurations []time.Duration // time to merge of pull requests merged in the time frame
+}
+
+func (s *ThroughputSummary) Union(other *ThroughputSummary) {
+	s.TimeFrame.Union(other.TimeFrame)
+	s.IssuesOpened += other.IssuesOpened
+	s.IssuesClosed += other.IssuesClosed
+	s.PullRequestsOpened += other.PullRequestsOpened
+	s.PullRequestsMerged += other.PullRequestsMerged
+	s.PullRequestsClosed += other.PullRequestsClosed
+	s.IssueDurations = append(s.IssueDurations, other.IssueDurations...)
+	s.PullRequestDurations = append(s.PullRequestDurations, other.PullRequestDurations...)
+}
+
+// SummarizeThroughput summarizes the issues and pull requests opened and
+// closed in each time bucket.
+// Unlike Summarize, items are counted in the bucket when they are closed
+// rather than when they are created. Durations are measured in the working
+// time of the calendar.
+func SummarizeThroughput(issues map[int]github.Issue, buckets []TimeFrame, cal *calendar.Calendar) []*ThroughputSummary {
+	summaries := make([]*ThroughputSummary, len(buckets))
+	for i, bucket := range buckets {
+		summaries[i] = &ThroughputSummary{TimeFrame: bucket}
+	}
+	for _, issue := range issues {
+		for _, summary := range summaries {
+			if summary.Contains(issue.CreatedAt) {
+				if issue.IsPullRequest() {
+					summary.PullRequestsOpened++
+				} else {
+					summary.IssuesOpened++
+				}
+			}
+			if issue.State != "closed" || issue.ClosedAt == nil || !summary.Contains(*issue.ClosedAt) {
+				continue
+			}
+			switch {
+			case issue.Merged():
+				summary.PullRequestsMerged++
+				summary.PullRequestDurations = append(summary.PullRequestDurations, IssueDuration(issue, cal))
+			case issue.IsPullRequest():
+				summary.PullRequestsClosed++
+			default:
+				summary.IssuesClosed++
+				summary.IssueDurations = append(summary.IssueDurations, IssueDuration(issue, cal))
+			}
+		}
+	}
+	return summaries
+}
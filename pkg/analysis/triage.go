@@ -0,0 +1,140 @@
+package analysis
+
+import (
+	"strings"
+	"time"
+
+	"github.com/shizhMSFT/gha/pkg/actor"
+	"github.com/shizhMSFT/gha/pkg/calendar"
+	"github.com/shizhMSFT/gha/pkg/container/set"
+	"github.com/shizhMSFT/gha/pkg/github"
+	"github.com/shizhMSFT/gha/pkg/sort"
+)
+
+// Triage actions.
+const (
+	TriageLabel     = "label"
+	TriageAssignee  = "assignee"
+	TriageMilestone = "milestone"
+)
+
+// TriageActions are the triage actions in the order of reporting.
+var TriageActions = []string{TriageLabel, TriageAssignee, TriageMilestone}
+
+// triageEvents maps timeline events to triage actions.
+var triageEvents = map[string]string{
+	github.EventLabeled:    TriageLabel,
+	github.EventAssigned:   TriageAssignee,
+	github.EventMilestoned: TriageMilestone,
+}
+
+type TriageSummary struct {
+	TimeFrame
+
+	Triaged         []time.Duration            // duration of the first triage action
+	Untriaged       []github.Issue             // open issues without triage
+	ClosedUntriaged []time.Duration            // duration of issues closed without triage
+	Actions         map[string][]time.Duration // duration of the first action by action
+	Triagers        map[string][]time.Duration // duration of the first triage action by triager
+}
+
+func NewTriageSummary() *TriageSummary {
+	return &TriageSummary{
+		Actions:  make(map[string][]time.Duration),
+		Triagers: make(map[string][]time.Duration),
+	}
+}
+
+// Union adds the triage of the other repository.
+func (s *TriageSummary) Union(other *TriageSummary) {
+	s.TimeFrame.Union(other.TimeFrame)
+	s.Triaged = append(s.Triaged, other.Triaged...)
+	s.Untriaged = append(s.Untriaged, other.Untriaged...)
+	s.ClosedUntriaged = append(s.ClosedUntriaged, other.ClosedUntriaged...)
+	for action, durations := range other.Actions {
+		s.Actions[action] = append(s.Actions[action], durations...)
+	}
+	for triager, durations := range other.Triagers {
+		s.Triagers[triager] = append(s.Triagers[triager], durations...)
+	}
+}
+
+type SummarizeTriageOptions struct {
+	TimeFrame TimeFrame
+	Issues    map[int]github.Issue
+	Timelines map[int][]github.TimelineEvent
+
+	// Maintainers restricts triage actions to the maintainers if not empty.
+	Maintainers set.Set[string]
+
+	Calendar *calendar.Calendar
+	Bots     *actor.Classifier // recognizes bots by login patterns if not nil
+}
+
+// SummarizeTriage summarizes the time to the first label, assignee and
+// milestone of the issues created in the time frame, using their timelines.
+// Triage actions by bots and by the authors of the issues are ignored.
+func SummarizeTriage(opts SummarizeTriageOptions) *TriageSummary {
+	// normalize maintainers
+	maintainers := set.New[string]()
+	for maintainer := range opts.Maintainers {
+		maintainers.Add(strings.ToLower(maintainer))
+	}
+
+	// summarize
+	summary := NewTriageSummary()
+	summary.TimeFrame = opts.TimeFrame
+	for number, events := range opts.Timelines {
+		issue, ok := opts.Issues[number]
+		if !ok || issue.IsPullRequest() {
+			continue
+		}
+		if !opts.TimeFrame.Contains(issue.CreatedAt) {
+			continue
+		}
+		triaged := false
+		acted := set.New[string]()
+		for _, event := range sortEvents(events) {
+			action, ok := triageEvents[event.Event]
+			if !ok {
+				continue
+			}
+			actor := event.Who()
+			if opts.Bots.IsBot(actor) || strings.EqualFold(actor.Login, issue.User.Login) {
+				continue
+			}
+			if len(maintainers) > 0 && !maintainers.Contains(strings.ToLower(actor.Login)) {
+				continue
+			}
+			duration := opts.Calendar.Duration(issue.CreatedAt, event.When())
+			if !acted.Contains(action) {
+				acted.Add(action)
+				summary.Actions[action] = append(summary.Actions[action], duration)
+			}
+			if !triaged {
+				triaged = true
+				summary.Triaged = append(summary.Triaged, duration)
+				summary.Triagers[actor.Login] = append(summary.Triagers[actor.Login], duration)
+			}
+		}
+		if !triaged {
+			if issue.ClosedAt != nil {
+				summary.ClosedUntriaged = append(summary.ClosedUntriaged, opts.Calendar.Duration(issue.CreatedAt, *issue.ClosedAt))
+			} else {
+				summary.Untriaged = append(summary.Untriaged, issue)
+			}
+		}
+	}
+	return summary
+}
+
+// OutOfSLA returns the open untriaged issues waiting longer than sla, sorted
+// by the waiting time in descending order, where the waiting time is measured
+// in the working time of cal. The issues must be in the same repository.
+func (s *TriageSummary) OutOfSLA(sla time.Duration, now time.Time, cal *calendar.Calendar) sort.MapEntrySlice[int, time.Duration] {
+	pending := make(map[int]time.Time, len(s.Untriaged))
+	for _, issue := range s.Untriaged {
+		pending[issue.Number] = issue.CreatedAt
+	}
+	return outOfSLA(nil, pending, sla, now, cal)
+}
@@ -0,0 +1,261 @@
+package calendar
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shizhMSFT/gha/pkg/container/set"
+	"gopkg.in/yaml.v3"
+)
+
+// Calendar is a working calendar with working hours on working days in a time
+// zone, excluding holidays.
+// A nil calendar counts the wall-clock time.
+type Calendar struct {
+	Location *time.Location
+	Days     [7]bool         // working days indexed by time.Weekday
+	Start    time.Duration   // start of working hours since midnight
+	End      time.Duration   // end of working hours since midnight
+	Holidays set.Set[string] // dates in the format of YYYY-MM-DD
+}
+
+// Default returns the calendar with working hours from 9:00 to 17:00 UTC,
+// Monday to Friday, without holidays.
+func Default() *Calendar {
+	return &Calendar{
+		Location: time.UTC,
+		Days:     [7]bool{time.Monday: true, time.Tuesday: true, time.Wednesday: true, time.Thursday: true, time.Friday: true},
+		Start:    9 * time.Hour,
+		End:      17 * time.Hour,
+		Holidays: set.New[string](),
+	}
+}
+
+// Parse parses a calendar file in YAML, where omitted fields default to the
+// ones of Default. For example,
+//
+//	timezone: Europe/Berlin
+//	workdays: [Mon, Tue, Wed, Thu, Fri]
+//	hours: 09:00-17:00
+//	holidays:
+//	  - 2022-12-25
+//	  - 2022-12-26
+func Parse(content []byte) (*Calendar, error) {
+	var file struct {
+		Timezone string   `yaml:"timezone"`
+		Workdays []string `yaml:"workdays"`
+		Hours    string   `yaml:"hours"`
+		Holidays []string `yaml:"holidays"`
+	}
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return nil, err
+	}
+	c := Default()
+	if file.Timezone != "" {
+		location, err := time.LoadLocation(file.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone: %w", err)
+		}
+		c.Location = location
+	}
+	if len(file.Workdays) > 0 {
+		c.Days = [7]bool{}
+		for _, name := range file.Workdays {
+			day, err := parseWeekday(name)
+			if err != nil {
+				return nil, err
+			}
+			c.Days[day] = true
+		}
+	}
+	if file.Hours != "" {
+		start, end, ok := strings.Cut(file.Hours, "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid working hours: %s", file.Hours)
+		}
+		var err error
+		if c.Start, err = parseTimeOfDay(start); err != nil {
+			return nil, err
+		}
+		if c.End, err = parseTimeOfDay(end); err != nil {
+			return nil, err
+		}
+		if c.Start >= c.End {
+			return nil, fmt.Errorf("invalid working hours: %s", file.Hours)
+		}
+	}
+	for _, holiday := range file.Holidays {
+		date, err := time.Parse(time.DateOnly, holiday)
+		if err != nil {
+			return nil, fmt.Errorf("invalid holiday: %w", err)
+		}
+		c.Holidays.Add(date.Format(time.DateOnly))
+	}
+	return c, nil
+}
+
+// ParseICal parses the events in an iCalendar file as holidays of the default
+// calendar, located in the X-WR-TIMEZONE of the file if any.
+// All-day events span from DTSTART to the day before DTEND, and recurring
+// events are not expanded.
+func ParseICal(content []byte) (*Calendar, error) {
+	c := Default()
+	var start, end time.Time
+	inEvent, found := false, false
+	for _, line := range unfoldICal(content) {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		name, _, _ = strings.Cut(name, ";") // drop parameters
+		switch strings.ToUpper(name) {
+		case "BEGIN":
+			if strings.EqualFold(value, "VCALENDAR") {
+				found = true
+			}
+			if strings.EqualFold(value, "VEVENT") {
+				inEvent = true
+				start, end = time.Time{}, time.Time{}
+			}
+		case "X-WR-TIMEZONE":
+			location, err := time.LoadLocation(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid time zone: %w", err)
+			}
+			c.Location = location
+		case "DTSTART", "DTEND":
+			if !inEvent {
+				continue
+			}
+			if len(value) < 8 {
+				return nil, fmt.Errorf("invalid %s: %s", name, value)
+			}
+			date, err := time.Parse("20060102", value[:8])
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %s", name, value)
+			}
+			if strings.EqualFold(name, "DTSTART") {
+				start = date
+			} else {
+				end = date
+			}
+		case "END":
+			if !inEvent || !strings.EqualFold(value, "VEVENT") {
+				continue
+			}
+			inEvent = false
+			if start.IsZero() {
+				continue
+			}
+			if !end.After(start) {
+				end = start.AddDate(0, 0, 1)
+			}
+			for date := start; date.Before(end); date = date.AddDate(0, 0, 1) {
+				c.Holidays.Add(date.Format(time.DateOnly))
+			}
+		}
+	}
+	if !found {
+		return nil, errors.New("invalid iCalendar file: no VCALENDAR")
+	}
+	return c, nil
+}
+
+// unfoldICal splits the content into lines, joining the folded lines starting
+// with a space or a tab.
+func unfoldICal(content []byte) []string {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func parseWeekday(name string) (time.Weekday, error) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(name, day.String()) || strings.EqualFold(name, day.String()[:3]) {
+			return day, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid working day: %s", name)
+}
+
+// parseTimeOfDay parses a time of day in the format of HH:MM, where 24:00 is
+// the end of the day.
+func parseTimeOfDay(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "24:00" {
+		return 24 * time.Hour, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day: %s", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// IsWorkingDay reports whether the day of t in the calendar location is a
+// working day and not a holiday.
+func (c *Calendar) IsWorkingDay(t time.Time) bool {
+	if c == nil {
+		return true
+	}
+	t = t.In(c.Location)
+	return c.Days[t.Weekday()] && !c.Holidays.Contains(t.Format(time.DateOnly))
+}
+
+// Day returns the working time of a working day, or 24 hours for a nil
+// calendar.
+func (c *Calendar) Day() time.Duration {
+	if c == nil {
+		return 24 * time.Hour
+	}
+	return c.End - c.Start
+}
+
+// Duration returns the working time from start to end, such as 8 hours for a
+// full working day of 9:00-17:00.
+func (c *Calendar) Duration(start, end time.Time) time.Duration {
+	if c == nil {
+		return end.Sub(start)
+	}
+	if end.Before(start) {
+		return -c.Duration(end, start)
+	}
+	start, end = start.In(c.Location), end.In(c.Location)
+	var working time.Duration
+	year, month, day := start.Date()
+	for date := time.Date(year, month, day, 0, 0, 0, 0, c.Location); date.Before(end); date = date.AddDate(0, 0, 1) {
+		if !c.IsWorkingDay(date) {
+			continue
+		}
+		year, month, day := date.Date()
+		from := time.Date(year, month, day, 0, 0, 0, int(c.Start), c.Location)
+		to := time.Date(year, month, day, 0, 0, 0, int(c.End), c.Location)
+		if from.Before(start) {
+			from = start
+		}
+		if to.After(end) {
+			to = end
+		}
+		if to.After(from) {
+			working += to.Sub(from)
+		}
+	}
+	return working
+}
+
+// Since returns the working time elapsed since t.
+func (c *Calendar) Since(t time.Time) time.Duration {
+	return c.Duration(t, time.Now())
+}
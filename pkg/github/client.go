@@ -81,6 +81,31 @@ func (c *Client) Snapshot(ctx context.Context, org, repo string, opts SnapshotOp
 	return snapshot, len(issues), nil
 }
 
+// Milestones takes a snapshot of all milestones in a repository.
+func (c *Client) Milestones(ctx context.Context, org, repo string) ([]byte, error) {
+	var milestones []json.RawMessage
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("https://api.github.com/repos/%s/%s/milestones?state=all&per_page=100&page=%d", org, repo, page)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.do(req)
+		if err != nil {
+			return nil, err
+		}
+		pagedMilestones, err := c.decodeResponse(resp)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", url, err)
+		}
+		milestones = append(milestones, pagedMilestones...)
+		if len(pagedMilestones) < 100 {
+			break
+		}
+	}
+	return json.Marshal(milestones)
+}
+
 // PullRequestReviews takes a snapshot of all reviews for a pull request.
 func (c *Client) PullRequestReviews(ctx context.Context, org, repo string, number int) ([]byte, error) {
 	var reviews []json.RawMessage
@@ -106,6 +131,58 @@ func (c *Client) PullRequestReviews(ctx context.Context, org, repo string, numbe
 	return json.Marshal(reviews)
 }
 
+// PullRequestReviewComments takes a snapshot of all review comments on the
+// diff of a pull request.
+func (c *Client) PullRequestReviewComments(ctx context.Context, org, repo string, number int) ([]byte, error) {
+	var comments []json.RawMessage
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d/comments?per_page=100&page=%d", org, repo, number, page)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.do(req)
+		if err != nil {
+			return nil, err
+		}
+		pagedComments, err := c.decodeResponse(resp)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", url, err)
+		}
+		comments = append(comments, pagedComments...)
+		if len(pagedComments) < 100 {
+			break
+		}
+	}
+	return json.Marshal(comments)
+}
+
+// PullRequestFiles takes a snapshot of all changed files for a pull request.
+// GitHub lists at most 3000 files for a pull request.
+func (c *Client) PullRequestFiles(ctx context.Context, org, repo string, number int) ([]byte, error) {
+	var files []json.RawMessage
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%d/files?per_page=100&page=%d", org, repo, number, page)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.do(req)
+		if err != nil {
+			return nil, err
+		}
+		pagedFiles, err := c.decodeResponse(resp)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", url, err)
+		}
+		files = append(files, pagedFiles...)
+		if len(pagedFiles) < 100 {
+			break
+		}
+	}
+	return json.Marshal(files)
+}
+
 // IssueComments takes a snapshot of all comments for an issue.
 func (c *Client) IssueComments(ctx context.Context, org, repo string, number int) ([]byte, error) {
 	var comments []json.RawMessage
@@ -131,6 +208,31 @@ func (c *Client) IssueComments(ctx context.Context, org, repo string, number int
 	return json.Marshal(comments)
 }
 
+// IssueTimeline takes a snapshot of all timeline events for an issue.
+func (c *Client) IssueTimeline(ctx context.Context, org, repo string, number int) ([]byte, error) {
+	var events []json.RawMessage
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("https://api.github.com/repos/%s/%s/issues/%d/timeline?per_page=100&page=%d", org, repo, number, page)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.do(req)
+		if err != nil {
+			return nil, err
+		}
+		pagedEvents, err := c.decodeResponse(resp)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", url, err)
+		}
+		events = append(events, pagedEvents...)
+		if len(pagedEvents) < 100 {
+			break
+		}
+	}
+	return json.Marshal(events)
+}
+
 func (c *Client) do(req *http.Request) (*http.Response, error) {
 	req.Header.Set("Accept", "application/vnd.github+json")
 	apiVersion := c.APIVersion
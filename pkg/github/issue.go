@@ -3,6 +3,7 @@ package github
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/shizhMSFT/gha/pkg/diff"
@@ -16,24 +17,56 @@ func (l Label) String() string {
 	return l.Name
 }
 
+// Account types.
+const (
+	AccountTypeUser         = "User"
+	AccountTypeBot          = "Bot"
+	AccountTypeOrganization = "Organization"
+)
+
 type Account struct {
 	Login string `json:"login"`
+	Type  string `json:"type,omitempty"`
 }
 
 func (a Account) String() string {
 	return a.Login
 }
 
+// IsBot reports whether the account is a GitHub App bot.
+func (a Account) IsBot() bool {
+	return a.Type == AccountTypeBot || strings.HasSuffix(a.Login, "[bot]")
+}
+
 type Milestone struct {
-	Title string `json:"title"`
+	Number    int        `json:"number,omitempty"`
+	Title     string     `json:"title"`
+	State     string     `json:"state,omitempty"`
+	DueOn     *time.Time `json:"due_on,omitempty"`
+	CreatedAt time.Time  `json:"created_at,omitempty"`
+	ClosedAt  *time.Time `json:"closed_at,omitempty"`
 }
 
 func (m Milestone) String() string {
 	return m.Title
 }
 
+// ParseMilestones parses a milestone snapshot and returns milestones by title.
+func ParseMilestones(jsonBytes []byte) (map[string]Milestone, error) {
+	var milestones []Milestone
+	if err := json.Unmarshal(jsonBytes, &milestones); err != nil {
+		return nil, err
+	}
+	milestoneMap := make(map[string]Milestone)
+	for _, milestone := range milestones {
+		milestoneMap[milestone.Title] = milestone
+	}
+	return milestoneMap, nil
+}
+
 type PullRequest struct {
 	MergedAt *time.Time `json:"merged_at"`
+	MergedBy *Account   `json:"merged_by,omitempty"`
 }
 
 // Issue is an abbreviated version of the GitHub issue type.
@@ -41,12 +74,15 @@ type Issue struct {
 	HTMLURL     string       `json:"html_url"`
 	Number      int          `json:"number"`
 	Title       string       `json:"title"`
+	Body        string       `json:"body"`
 	User        Account      `json:"user"`
 	Labels      []Label      `json:"labels"`
 	Assignees   []Account    `json:"assignees"`
 	State       string       `json:"state"`
 	Milestone   Milestone    `json:"milestone"`
+	Comments    int          `json:"comments"`
 	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
 	ClosedAt    *time.Time   `json:"closed_at"`
 	PullRequest *PullRequest `json:"pull_request,omitempty"`
 }
@@ -5,10 +5,21 @@ import (
 	"time"
 )
 
+// Pull request review states.
+const (
+	ReviewStateApproved         = "APPROVED"
+	ReviewStateChangesRequested = "CHANGES_REQUESTED"
+	ReviewStateCommented        = "COMMENTED"
+	ReviewStateDismissed        = "DISMISSED"
+	ReviewStatePending          = "PENDING"
+)
+
 type PullRequestReview struct {
+	ID          int64     `json:"id"`
 	User        Account   `json:"user"`
 	State       string    `json:"state"`
 	SubmittedAt time.Time `json:"submitted_at"`
+	CommitID    string    `json:"commit_id,omitempty"` // head commit of the pull request when reviewed
 }
 
 func ParsePullRequestReviews(jsonBytes []byte) (map[int][]PullRequestReview, error) {
@@ -18,3 +29,36 @@ func ParsePullRequestReviews(jsonBytes []byte) (map[int][]PullRequestReview, err
 	}
 	return reviews, nil
 }
+
+// PullRequestReviewComment is a comment on the diff of a pull request.
+type PullRequestReviewComment struct {
+	User      Account   `json:"user"`
+	Path      string    `json:"path"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func ParsePullRequestReviewComments(jsonBytes []byte) (map[int][]PullRequestReviewComment, error) {
+	var comments map[int][]PullRequestReviewComment
+	if err := json.Unmarshal(jsonBytes, &comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// PullRequestFile is a file changed by a pull request.
+type PullRequestFile struct {
+	Filename         string `json:"filename"`
+	PreviousFilename string `json:"previous_filename,omitempty"`
+	Status           string `json:"status"`
+	Additions        int    `json:"additions"`
+	Deletions        int    `json:"deletions"`
+	Changes          int    `json:"changes"`
+}
+
+func ParsePullRequestFiles(jsonBytes []byte) (map[int][]PullRequestFile, error) {
+	var files map[int][]PullRequestFile
+	if err := json.Unmarshal(jsonBytes, &files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
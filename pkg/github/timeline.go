@@ -0,0 +1,77 @@
+package github
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Issue timeline event types.
+const (
+	EventAssigned        = "assigned"
+	EventClosed          = "closed"
+	EventCommented       = "commented"
+	EventCrossReferenced = "cross-referenced"
+	EventLabeled         = "labeled"
+	EventMerged          = "merged"
+	EventMilestoned      = "milestoned"
+	EventReopened        = "reopened"
+	EventReviewDismissed = "review_dismissed"
+	EventReviewed        = "reviewed"
+)
+
+// TimelineEvent is an abbreviated version of the GitHub issue timeline event
+// type.
+type TimelineEvent struct {
+	Event     string     `json:"event"`
+	Actor     Account    `json:"actor"`
+	CreatedAt time.Time  `json:"created_at"`
+	Label     *Label     `json:"label,omitempty"`
+	Assignee  *Account   `json:"assignee,omitempty"`
+	Milestone *Milestone `json:"milestone,omitempty"`
+	Source    *Source    `json:"source,omitempty"`
+	CommitID  string     `json:"commit_id,omitempty"`
+
+	DismissedReview *DismissedReview `json:"dismissed_review,omitempty"`
+
+	// User and SubmittedAt are set instead of Actor and CreatedAt for
+	// reviewed events.
+	User        Account   `json:"user"`
+	SubmittedAt time.Time `json:"submitted_at"`
+}
+
+// Source is the source of a cross-referenced event.
+type Source struct {
+	Type  string `json:"type"`
+	Issue *Issue `json:"issue,omitempty"`
+}
+
+// DismissedReview is the review dismissed by a review_dismissed event.
+type DismissedReview struct {
+	State            string `json:"state"` // state before dismissal in lower case
+	ReviewID         int64  `json:"review_id"`
+	DismissalMessage string `json:"dismissal_message,omitempty"`
+}
+
+// Who returns the account performing the event.
+func (e TimelineEvent) Who() Account {
+	if e.Actor.Login == "" {
+		return e.User
+	}
+	return e.Actor
+}
+
+// When returns the time of the event.
+func (e TimelineEvent) When() time.Time {
+	if e.CreatedAt.IsZero() {
+		return e.SubmittedAt
+	}
+	return e.CreatedAt
+}
+
+func ParseTimelines(jsonBytes []byte) (map[int][]TimelineEvent, error) {
+	var timelines map[int][]TimelineEvent
+	if err := json.Unmarshal(jsonBytes, &timelines); err != nil {
+		return nil, err
+	}
+	return timelines, nil
+}
@@ -0,0 +1,130 @@
+package identity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Affiliation is the organization of a person in a time range.
+// A zero From or Until leaves the range open.
+type Affiliation struct {
+	Organization string    `yaml:"organization"`
+	From         time.Time `yaml:"from"`
+	Until        time.Time `yaml:"until"`
+}
+
+// Contains reports whether the affiliation is effective at t.
+// Until is inclusive to the end of its day.
+func (a Affiliation) Contains(t time.Time) bool {
+	if !a.From.IsZero() && t.Before(a.From) {
+		return false
+	}
+	return a.Until.IsZero() || t.Before(a.Until.AddDate(0, 0, 1))
+}
+
+// Person is a person with GitHub accounts.
+type Person struct {
+	Logins       []string      `yaml:"logins"`
+	Affiliations []Affiliation `yaml:"affiliations"`
+	Timezone     string        `yaml:"timezone"` // IANA time zone, such as America/Los_Angeles
+}
+
+// Map maps GitHub accounts to people and their organizations.
+// A nil map maps each account to itself without an organization.
+type Map struct {
+	persons   map[string]string // person by lowercase login
+	people    map[string]Person
+	locations map[string]*time.Location // time zones by person
+}
+
+// Parse parses an identity file in YAML, which maps people to their accounts
+// and affiliations. For example,
+//
+//	people:
+//	  Jane Doe:
+//	    logins: [jdoe, jane-corp]
+//	    affiliations:
+//	      - organization: Contoso
+//	        until: 2022-06-30
+//	      - organization: Fabrikam
+//	        from: 2022-07-01
+//	    timezone: Europe/London
+func Parse(content []byte) (*Map, error) {
+	var file struct {
+		People map[string]Person `yaml:"people"`
+	}
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return nil, err
+	}
+	m := &Map{
+		persons:   make(map[string]string),
+		people:    file.People,
+		locations: make(map[string]*time.Location),
+	}
+	for name, person := range file.People {
+		if person.Timezone != "" {
+			location, err := time.LoadLocation(person.Timezone)
+			if err != nil {
+				return nil, fmt.Errorf("invalid time zone of %q: %w", name, err)
+			}
+			m.locations[name] = location
+		}
+		for _, login := range person.Logins {
+			key := strings.ToLower(login)
+			if other, ok := m.persons[key]; ok && other != name {
+				return nil, fmt.Errorf("login %q belongs to both %q and %q", login, other, name)
+			}
+			m.persons[key] = name
+		}
+	}
+	return m, nil
+}
+
+// Person returns the person owning the login, or the login itself if the
+// owner is unknown.
+func (m *Map) Person(login string) string {
+	if m == nil {
+		return login
+	}
+	if person, ok := m.persons[strings.ToLower(login)]; ok {
+		return person
+	}
+	return login
+}
+
+// Same reports whether the logins are owned by the same person. Logins are
+// compared case-insensitively.
+func (m *Map) Same(login, other string) bool {
+	return strings.EqualFold(m.Person(login), m.Person(other))
+}
+
+// Organization returns the organization of the person owning the login at t,
+// or an empty string if unknown.
+func (m *Map) Organization(login string, t time.Time) string {
+	if m == nil {
+		return ""
+	}
+	person, ok := m.people[m.persons[strings.ToLower(login)]]
+	if !ok {
+		return ""
+	}
+	for _, affiliation := range person.Affiliations {
+		if affiliation.Contains(t) {
+			return affiliation.Organization
+		}
+	}
+	return ""
+}
+
+// Location returns the time zone of the person owning the login, or false if
+// unknown.
+func (m *Map) Location(login string) (*time.Location, bool) {
+	if m == nil {
+		return nil, false
+	}
+	location, ok := m.locations[m.persons[strings.ToLower(login)]]
+	return location, ok
+}
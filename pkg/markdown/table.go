@@ -5,6 +5,7 @@ import (
 	"io"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 type Table struct {
@@ -18,7 +19,7 @@ func NewTable(header ...string) *Table {
 	size := make([]int, 0, len(header))
 	for _, v := range header {
 		row = append(row, v)
-		size = append(size, len(v))
+		size = append(size, utf8.RuneCountInString(v))
 	}
 	return &Table{
 		header: row,
@@ -39,8 +40,8 @@ func (t *Table) AddRow(row ...any) {
 			item = fmt.Sprintf("%v", row)
 		}
 		body[i] = item
-		if len(item) > t.size[i] {
-			t.size[i] = len(item)
+		if n := utf8.RuneCountInString(item); n > t.size[i] {
+			t.size[i] = n
 		}
 	}
 	t.body = append(t.body, body)
@@ -0,0 +1,42 @@
+package math
+
+import (
+	"math/rand"
+	"slices"
+
+	"golang.org/x/exp/constraints"
+)
+
+// BootstrapDifference estimates the confidence interval of stat(b) - stat(a)
+// by resampling a and b with replacement for the given number of iterations.
+// stat is called with sorted samples.
+// It returns the lower and upper bounds of the two-sided confidence interval
+// at the confidence level, such as 0.95.
+func BootstrapDifference[T constraints.Integer | constraints.Float](a, b []T, stat func([]T) T, iterations int, confidence float64, rng *rand.Rand) (float64, float64) {
+	if len(a) == 0 || len(b) == 0 {
+		panic("empty slice")
+	}
+	if confidence <= 0 || confidence >= 1 {
+		panic("invalid confidence")
+	}
+	sampleA := make([]T, len(a))
+	sampleB := make([]T, len(b))
+	diffs := make([]float64, iterations)
+	for i := range diffs {
+		resample(sampleA, a, rng)
+		resample(sampleB, b, rng)
+		diffs[i] = float64(stat(sampleB)) - float64(stat(sampleA))
+	}
+	slices.Sort(diffs)
+	alpha := (1 - confidence) / 2
+	return Percentile(diffs, alpha), Percentile(diffs, 1-alpha)
+}
+
+// resample fills dst with values drawn from src with replacement, and sorts
+// dst.
+func resample[T constraints.Ordered](dst, src []T, rng *rand.Rand) {
+	for i := range dst {
+		dst[i] = src[rng.Intn(len(src))]
+	}
+	slices.Sort(dst)
+}
@@ -0,0 +1,73 @@
+package math
+
+import (
+	"cmp"
+	"slices"
+
+	"golang.org/x/exp/constraints"
+)
+
+// BusFactor returns the fewest number of values in s whose sum covers the
+// share of the total, such as 0.5 for a half.
+// s must not contain negative values. It returns 0 if the total is 0.
+func BusFactor[T constraints.Integer | constraints.Float](s []T, share float64) int {
+	if share < 0 || share > 1 {
+		panic("invalid share")
+	}
+	total := sum(s)
+	if total == 0 {
+		return 0
+	}
+	sorted := slices.Clone(s)
+	slices.SortFunc(sorted, func(a, b T) int {
+		return cmp.Compare(b, a)
+	})
+	var covered float64
+	for i, x := range sorted {
+		covered += float64(x)
+		if covered >= share*total {
+			return i + 1
+		}
+	}
+	return len(sorted)
+}
+
+// Gini returns the Gini coefficient of the values in s, where 0 means perfect
+// equality and values close to 1 mean concentration on a few.
+// s must not contain negative values. It returns 0 if the total is 0.
+func Gini[T constraints.Integer | constraints.Float](s []T) float64 {
+	total := sum(s)
+	if total == 0 {
+		return 0
+	}
+	sorted := slices.Clone(s)
+	slices.Sort(sorted)
+	var weighted float64
+	for i, x := range sorted {
+		weighted += float64(i+1) * float64(x)
+	}
+	n := float64(len(sorted))
+	return 2*weighted/(n*total) - (n+1)/n
+}
+
+// TopShare returns the share of the total covered by the n largest values in
+// s. It returns 0 if the total is 0.
+func TopShare[T constraints.Integer | constraints.Float](s []T, n int) float64 {
+	total := sum(s)
+	if total == 0 {
+		return 0
+	}
+	sorted := slices.Clone(s)
+	slices.SortFunc(sorted, func(a, b T) int {
+		return cmp.Compare(b, a)
+	})
+	return sum(sorted[:min(n, len(sorted))]) / total
+}
+
+func sum[T constraints.Integer | constraints.Float](s []T) float64 {
+	var total float64
+	for _, x := range s {
+		total += float64(x)
+	}
+	return total
+}
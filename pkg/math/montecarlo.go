@@ -0,0 +1,40 @@
+package math
+
+import "math/rand"
+
+// SimulatePeriods simulates the number of periods needed to complete the
+// items, by resampling the throughput per period from samples in each run.
+// A run giving up after maxPeriods periods results in maxPeriods+1.
+func SimulatePeriods(samples []int, items, runs, maxPeriods int, rng *rand.Rand) []int {
+	if len(samples) == 0 {
+		panic("empty slice")
+	}
+	results := make([]int, runs)
+	for i := range results {
+		remaining := items
+		periods := 0
+		for remaining > 0 && periods <= maxPeriods {
+			remaining -= samples[rng.Intn(len(samples))]
+			periods++
+		}
+		results[i] = periods
+	}
+	return results
+}
+
+// SimulateThroughput simulates the number of items completed in the periods,
+// by resampling the throughput per period from samples in each run.
+func SimulateThroughput(samples []int, periods, runs int, rng *rand.Rand) []int {
+	if len(samples) == 0 {
+		panic("empty slice")
+	}
+	results := make([]int, runs)
+	for i := range results {
+		total := 0
+		for j := 0; j < periods; j++ {
+			total += samples[rng.Intn(len(samples))]
+		}
+		results[i] = total
+	}
+	return results
+}
@@ -0,0 +1,86 @@
+package math
+
+import (
+	"slices"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Survival is a survival function estimated from right-censored data.
+type Survival[T constraints.Integer | constraints.Float] struct {
+	Times         []T       // distinct event times in ascending order
+	Probabilities []float64 // survival probability right after each event time
+}
+
+// KaplanMeier estimates the survival function with the Kaplan-Meier estimator,
+// where events are the times of observed events and censored are the times of
+// subjects last seen without the event.
+// Subjects censored at an event time are considered at risk at that time.
+func KaplanMeier[T constraints.Integer | constraints.Float](events, censored []T) *Survival[T] {
+	events = slices.Clone(events)
+	slices.Sort(events)
+	censored = slices.Clone(censored)
+	slices.Sort(censored)
+
+	survival := &Survival[T]{}
+	atRisk := len(events) + len(censored)
+	probability := 1.0
+	var c int
+	for i := 0; i < len(events); {
+		t := events[i]
+		// remove subjects censored before t from the risk set
+		for c < len(censored) && censored[c] < t {
+			atRisk--
+			c++
+		}
+		died := 0
+		for i < len(events) && events[i] == t {
+			died++
+			i++
+		}
+		probability *= 1 - float64(died)/float64(atRisk)
+		atRisk -= died
+		survival.Times = append(survival.Times, t)
+		survival.Probabilities = append(survival.Probabilities, probability)
+	}
+	return survival
+}
+
+// At returns the probability of surviving beyond t.
+func (s *Survival[T]) At(t T) float64 {
+	i, found := slices.BinarySearch(s.Times, t)
+	if found {
+		return s.Probabilities[i]
+	}
+	if i == 0 {
+		return 1
+	}
+	return s.Probabilities[i-1]
+}
+
+// CDF returns the probability of the event happening by t.
+func (s *Survival[T]) CDF(t T) float64 {
+	return 1 - s.At(t)
+}
+
+// Percentile returns the earliest time by which the event has happened with
+// probability p.
+// It returns false if the estimate does not reach p due to censoring.
+func (s *Survival[T]) Percentile(p float64) (T, bool) {
+	if p < 0 || p > 1 {
+		panic("invalid percentile")
+	}
+	for i, probability := range s.Probabilities {
+		if 1-probability >= p-1e-9 {
+			return s.Times[i], true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// Median returns the median survival time.
+// It returns false if fewer than half of the subjects had the event.
+func (s *Survival[T]) Median() (T, bool) {
+	return s.Percentile(0.5)
+}
@@ -0,0 +1,115 @@
+package owners
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ParseCODEOWNERS parses a GitHub CODEOWNERS file.
+// Email owners are ignored as they cannot be mapped to GitHub accounts.
+func ParseCODEOWNERS(content []byte) (*File, error) {
+	file := &File{Format: FormatCODEOWNERS}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		fields := strings.Fields(stripComment(scanner.Text()))
+		if len(fields) == 0 {
+			continue
+		}
+		match, err := compilePattern(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		rule := Rule{
+			Pattern: fields[0],
+			match:   match,
+		}
+		for _, field := range fields[1:] {
+			login, ok := strings.CutPrefix(field, "@")
+			if !ok {
+				continue
+			}
+			rule.Owners = append(rule.Owners, Owner{Login: login, Role: RoleOwner})
+		}
+		file.Rules = append(file.Rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// stripComment removes the comment from a CODEOWNERS line, which starts with
+// an unescaped `#` at the beginning of the line or after whitespace, so that
+// `#` inside a pattern, e.g. `a#b` or `\#notes`, is kept.
+func stripComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++ // skip the escaped character
+		case '#':
+			if i == 0 || line[i-1] == ' ' || line[i-1] == '\t' {
+				return line[:i]
+			}
+		}
+	}
+	return line
+}
+
+// compilePattern compiles a gitignore-style path pattern used by CODEOWNERS.
+func compilePattern(pattern string) (func(string) bool, error) {
+	pattern = strings.ReplaceAll(pattern, `\#`, "#")
+	anchored := strings.HasPrefix(pattern, "/")
+	directory := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	if strings.Contains(pattern, "/") {
+		anchored = true
+	}
+	if pattern == "" {
+		// the root directory matches everything
+		return func(string) bool { return true }, nil
+	}
+	// a pattern matches everything inside the directories it matches, unless
+	// its last segment has wildcards, e.g. `docs/*` does not match
+	// `docs/a/b.md`
+	last := pattern[strings.LastIndex(pattern, "/")+1:]
+	subtree := directory || !strings.ContainsAny(last, "*?")
+
+	expr := new(strings.Builder)
+	if anchored {
+		expr.WriteString("^")
+	} else {
+		expr.WriteString("^(?:.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if strings.HasPrefix(pattern[i:], "**/") {
+				expr.WriteString("(?:.*/)?")
+				i += 2
+			} else if strings.HasPrefix(pattern[i:], "**") {
+				expr.WriteString(".*")
+				i++
+			} else {
+				expr.WriteString("[^/]*")
+			}
+		case '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	if subtree {
+		expr.WriteString("(?:/.*)?")
+	}
+	expr.WriteString("$")
+
+	re, err := regexp.Compile(expr.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %q: %w", pattern, err)
+	}
+	return re.MatchString, nil
+}
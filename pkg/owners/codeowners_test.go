@@ -0,0 +1,113 @@
+package owners
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCompilePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		// unanchored patterns match at any depth
+		{"*", "a.go", true},
+		{"*", "a/b/c.go", true},
+		{"*.js", "a.js", true},
+		{"*.js", "a/b/c.js", true},
+		{"*.js", "a/b/c.go", false},
+		{"apps/", "apps/a.go", true},
+		{"apps/", "x/apps/a/b.go", true},
+		{"apps/", "apps", true},
+		{"logs", "x/logs/a.txt", true},
+
+		// anchored patterns
+		{"/docs/", "docs/a.md", true},
+		{"/docs/", "docs/a/b.md", true},
+		{"/docs/", "x/docs/a.md", false},
+		{"/build/logs/", "build/logs/a/b.log", true},
+		{"/build/logs/", "x/build/logs/a.log", false},
+		{"docs/getting-started.md", "docs/getting-started.md", true},
+		{"docs/getting-started.md", "x/docs/getting-started.md", false},
+
+		// wildcards in the last segment do not match nested files
+		{"docs/*", "docs/a.md", true},
+		{"docs/*", "docs/a/b.md", false},
+		{"/docs/*.md", "docs/a.md", true},
+		{"/docs/*.md", "docs/a/b.md", false},
+		{"docs/?.md", "docs/a.md", true},
+		{"docs/?.md", "docs/ab.md", false},
+
+		// double asterisks
+		{"**/logs", "logs/a.log", true},
+		{"**/logs", "a/b/logs/c.log", true},
+		{"docs/**", "docs/a/b.md", true},
+		{"docs/**/*.md", "docs/a/b/c.md", true},
+		{"docs/**/*.md", "docs/a.md", true},
+		{"docs/**/*.md", "docs/a/b.go", false},
+
+		// escaped hash
+		{`\#notes`, "#notes", true},
+		{"/", "a/b.go", true},
+	}
+	for _, tt := range tests {
+		match, err := compilePattern(tt.pattern)
+		if err != nil {
+			t.Errorf("compilePattern(%q) error = %v", tt.pattern, err)
+			continue
+		}
+		if got := match(tt.name); got != tt.want {
+			t.Errorf("compilePattern(%q)(%q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseCODEOWNERS(t *testing.T) {
+	content := []byte(`# comment
+*           @global
+/docs/      @org/docs  # trailing comment
+docs/*.go   @gopher user@example.com
+/vendor/
+\#notes/    @scribe # @commented
+issue#1.md  @tracker#1 #@commented
+`)
+	file, err := ParseCODEOWNERS(content)
+	if err != nil {
+		t.Fatalf("ParseCODEOWNERS() error = %v", err)
+	}
+	tests := []struct {
+		name    string
+		pattern string
+		owners  []string
+	}{
+		{"main.go", "*", []string{"global"}},
+		{"docs/a.md", "/docs/", []string{"org/docs"}},
+		{"docs/a.go", "docs/*.go", []string{"gopher"}},
+		{"docs/a/b.go", "/docs/", []string{"org/docs"}},
+		{"vendor/a/b.go", "/vendor/", nil},
+		{"#notes/a.md", `\#notes/`, []string{"scribe"}},
+		{"issue#1.md", "issue#1.md", []string{"tracker#1"}},
+	}
+	for _, tt := range tests {
+		rule, ok := file.Match(tt.name)
+		if !ok {
+			t.Errorf("Match(%q) not found", tt.name)
+			continue
+		}
+		if rule.Pattern != tt.pattern {
+			t.Errorf("Match(%q).Pattern = %q, want %q", tt.name, rule.Pattern, tt.pattern)
+		}
+		if got := logins(rule.Owners); !slices.Equal(got, tt.owners) {
+			t.Errorf("Match(%q).Owners = %v, want %v", tt.name, got, tt.owners)
+		}
+	}
+}
+
+func logins(owners []Owner) []string {
+	var logins []string
+	for _, owner := range owners {
+		logins = append(logins, owner.Login)
+	}
+	return logins
+}
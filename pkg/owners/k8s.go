@@ -0,0 +1,74 @@
+package owners
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+
+	"golang.org/x/exp/maps"
+	"gopkg.in/yaml.v3"
+)
+
+// ownersConfig is the schema of a Kubernetes OWNERS file.
+// Reference: https://www.kubernetes.dev/docs/guide/owners/
+type ownersConfig struct {
+	Approvers         []string                     `yaml:"approvers"`
+	Reviewers         []string                     `yaml:"reviewers"`
+	EmeritusApprovers []string                     `yaml:"emeritus_approvers"`
+	Filters           map[string]ownersConfigEntry `yaml:"filters"`
+}
+
+type ownersConfigEntry struct {
+	Approvers []string `yaml:"approvers"`
+	Reviewers []string `yaml:"reviewers"`
+}
+
+// ParseOWNERS parses a Kubernetes OWNERS file located at the repository root.
+// Top-level owners apply to all paths while filter owners apply to the file
+// paths matching the filter regular expressions.
+// Owners of all matching rules are combined by File.Match, so the rules are
+// sorted by pattern only to be deterministic.
+func ParseOWNERS(content []byte) (*File, error) {
+	var config ownersConfig
+	if err := yaml.Unmarshal(content, &config); err != nil {
+		return nil, err
+	}
+
+	file := &File{Format: FormatOWNERS}
+	if owners := newOwners(config.Approvers, config.Reviewers, config.EmeritusApprovers); len(owners) > 0 {
+		file.Rules = append(file.Rules, Rule{
+			Pattern: "*",
+			Owners:  owners,
+			match:   func(string) bool { return true },
+		})
+	}
+	patterns := maps.Keys(config.Filters)
+	slices.Sort(patterns)
+	for _, pattern := range patterns {
+		entry := config.Filters[pattern]
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter: %q: %w", pattern, err)
+		}
+		file.Rules = append(file.Rules, Rule{
+			Pattern: pattern,
+			Owners:  newOwners(entry.Approvers, entry.Reviewers, nil),
+			match:   re.MatchString,
+		})
+	}
+	return file, nil
+}
+
+func newOwners(approvers, reviewers, emeritus []string) []Owner {
+	owners := make([]Owner, 0, len(approvers)+len(reviewers)+len(emeritus))
+	for _, login := range approvers {
+		owners = append(owners, Owner{Login: login, Role: RoleApprover})
+	}
+	for _, login := range reviewers {
+		owners = append(owners, Owner{Login: login, Role: RoleReviewer})
+	}
+	for _, login := range emeritus {
+		owners = append(owners, Owner{Login: login, Role: RoleEmeritus})
+	}
+	return owners
+}
@@ -0,0 +1,66 @@
+package owners
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseOWNERS(t *testing.T) {
+	content := []byte(`filters:
+  ".*":
+    approvers: [alice]
+  "\\.go$":
+    approvers: [bob]
+    reviewers: [carol]
+  "^docs/":
+    approvers: [dave, alice]
+`)
+	file, err := ParseOWNERS(content)
+	if err != nil {
+		t.Fatalf("ParseOWNERS() error = %v", err)
+	}
+	tests := []struct {
+		name    string
+		pattern string
+		owners  []string
+	}{
+		{"README.md", ".*", []string{"alice"}},
+		{"main.go", `.* | \.go$`, []string{"alice", "bob", "carol"}},
+		{"docs/a.md", ".* | ^docs/", []string{"alice", "dave"}},
+		{"docs/a.go", `.* | \.go$ | ^docs/`, []string{"alice", "bob", "carol", "dave"}},
+	}
+	for _, tt := range tests {
+		rule, ok := file.Match(tt.name)
+		if !ok {
+			t.Errorf("Match(%q) not found", tt.name)
+			continue
+		}
+		if rule.Pattern != tt.pattern {
+			t.Errorf("Match(%q).Pattern = %q, want %q", tt.name, rule.Pattern, tt.pattern)
+		}
+		if got := logins(rule.Owners); !slices.Equal(got, tt.owners) {
+			t.Errorf("Match(%q).Owners = %v, want %v", tt.name, got, tt.owners)
+		}
+	}
+}
+
+func TestParseOWNERSTopLevel(t *testing.T) {
+	content := []byte(`approvers: [alice]
+reviewers: [bob]
+emeritus_approvers: [carol]
+`)
+	file, err := ParseOWNERS(content)
+	if err != nil {
+		t.Fatalf("ParseOWNERS() error = %v", err)
+	}
+	rule, ok := file.Match("any/path.go")
+	if !ok {
+		t.Fatal("Match() not found")
+	}
+	if got, want := logins(rule.Owners), []string{"alice", "bob", "carol"}; !slices.Equal(got, want) {
+		t.Errorf("Match().Owners = %v, want %v", got, want)
+	}
+	if got, want := file.Maintainers(), []string{"alice"}; !slices.Equal(got, want) {
+		t.Errorf("Maintainers() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,105 @@
+package owners
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+var (
+	// handleRegexp matches GitHub handles like `@login` but not emails.
+	handleRegexp = regexp.MustCompile(`(?:[^\w]+@|^@)([\w-]+)`)
+
+	// profileRegexp matches GitHub profile links.
+	profileRegexp = regexp.MustCompile(`github\.com/([\w-]+)/?(?:[^\w/-]|$)`)
+)
+
+// ParseMaintainers parses a maintainer list in plain text or markdown, such as
+// MAINTAINERS or MAINTAINERS.md.
+// Owners are listed with their GitHub handles or profile links in list items,
+// table rows, or plain lines. The role of the owners is inferred from the
+// section heading or, for tables, the `Role` column.
+func ParseMaintainers(content []byte) (*File, error) {
+	rule := Rule{
+		Pattern: "*",
+		match:   func(string) bool { return true },
+	}
+	seen := make(map[string]struct{})
+	role := RoleMaintainer
+	roleColumn := -1
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if heading, ok := strings.CutPrefix(line, "#"); ok {
+			role = parseRole(heading, RoleMaintainer)
+			roleColumn = -1
+			continue
+		}
+
+		lineRole := role
+		isTable := strings.HasPrefix(line, "|")
+		if isTable {
+			cells := strings.Split(strings.Trim(line, "|"), "|")
+			if roleColumn < 0 {
+				for i, cell := range cells {
+					if strings.EqualFold(strings.TrimSpace(cell), "role") {
+						roleColumn = i
+					}
+				}
+			} else if roleColumn < len(cells) {
+				lineRole = parseRole(cells[roleColumn], role)
+			}
+		} else if !isListItem(line) {
+			roleColumn = -1
+		}
+
+		var logins []string
+		for _, match := range handleRegexp.FindAllStringSubmatch(line, -1) {
+			logins = append(logins, match[1])
+		}
+		if isTable || isListItem(line) {
+			for _, match := range profileRegexp.FindAllStringSubmatch(line, -1) {
+				logins = append(logins, match[1])
+			}
+		}
+		for _, login := range logins {
+			key := strings.ToLower(login)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			rule.Owners = append(rule.Owners, Owner{Login: login, Role: lineRole})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	file := &File{Format: FormatMaintainers}
+	if len(rule.Owners) > 0 {
+		file.Rules = append(file.Rules, rule)
+	}
+	return file, nil
+}
+
+// parseRole infers the role from a heading or a role description.
+func parseRole(text string, fallback Role) Role {
+	text = strings.ToLower(text)
+	for _, keyword := range []string{"emeritus", "alumni", "former", "retired", "inactive"} {
+		if strings.Contains(text, keyword) {
+			return RoleEmeritus
+		}
+	}
+	switch {
+	case strings.Contains(text, "reviewer"):
+		return RoleReviewer
+	case strings.Contains(text, "maintainer"), strings.Contains(text, "approver"), strings.Contains(text, "owner"):
+		return RoleMaintainer
+	}
+	return fallback
+}
+
+func isListItem(line string) bool {
+	return strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ") || strings.HasPrefix(line, "+ ")
+}
@@ -0,0 +1,154 @@
+package owners
+
+import (
+	"path"
+	"strings"
+)
+
+// Format is the format of an ownership file.
+type Format string
+
+// Supported ownership file formats.
+const (
+	FormatCODEOWNERS  Format = "CODEOWNERS"
+	FormatOWNERS      Format = "OWNERS"
+	FormatMaintainers Format = "MAINTAINERS"
+)
+
+// Role is the role of an owner.
+type Role string
+
+// Known roles of owners.
+const (
+	RoleOwner      Role = "owner"      // code owner in CODEOWNERS
+	RoleApprover   Role = "approver"   // approver in OWNERS
+	RoleReviewer   Role = "reviewer"   // reviewer in OWNERS or MAINTAINERS
+	RoleMaintainer Role = "maintainer" // maintainer in MAINTAINERS
+	RoleEmeritus   Role = "emeritus"   // former approver or maintainer
+)
+
+// Owner is a GitHub account or team owning a path.
+type Owner struct {
+	Login string // user login, or `org/team` for teams
+	Role  Role
+}
+
+// IsTeam reports whether the owner is a GitHub team.
+func (o Owner) IsTeam() bool {
+	return strings.Contains(o.Login, "/")
+}
+
+func (o Owner) String() string {
+	return "@" + o.Login
+}
+
+// Rule assigns owners to the paths matching the pattern.
+type Rule struct {
+	Pattern string
+	Owners  []Owner
+
+	match func(name string) bool
+}
+
+// Match reports whether the rule applies to the file path name.
+func (r Rule) Match(name string) bool {
+	if r.match == nil {
+		return false
+	}
+	return r.match(strings.TrimPrefix(name, "/"))
+}
+
+// File is a parsed ownership file.
+type File struct {
+	Format Format
+	Rules  []Rule
+}
+
+// Parse parses the ownership file content, where the format is detected by the
+// file name.
+// Files other than CODEOWNERS and OWNERS are parsed as maintainer lists, such
+// as MAINTAINERS or MAINTAINERS.md.
+func Parse(name string, content []byte) (*File, error) {
+	switch strings.ToUpper(path.Base(strings.ReplaceAll(name, "\\", "/"))) {
+	case "CODEOWNERS":
+		return ParseCODEOWNERS(content)
+	case "OWNERS":
+		return ParseOWNERS(content)
+	default:
+		return ParseMaintainers(content)
+	}
+}
+
+// Match returns the rule applying to the file path name.
+// As with CODEOWNERS, the last matching rule takes precedence. As with
+// Kubernetes OWNERS, whose filters are additive, the matching rules are
+// combined into a rule with the patterns joined by ` | ` and the owners of
+// all of them.
+func (f *File) Match(name string) (Rule, bool) {
+	if f.Format != FormatOWNERS {
+		for i := len(f.Rules) - 1; i >= 0; i-- {
+			if f.Rules[i].Match(name) {
+				return f.Rules[i], true
+			}
+		}
+		return Rule{}, false
+	}
+
+	var matched []Rule
+	for _, rule := range f.Rules {
+		if rule.Match(name) {
+			matched = append(matched, rule)
+		}
+	}
+	switch len(matched) {
+	case 0:
+		return Rule{}, false
+	case 1:
+		return matched[0], true
+	}
+	patterns := make([]string, 0, len(matched))
+	var combined []Owner
+	seen := make(map[Owner]struct{})
+	for _, rule := range matched {
+		patterns = append(patterns, rule.Pattern)
+		for _, owner := range rule.Owners {
+			if _, ok := seen[owner]; ok {
+				continue
+			}
+			seen[owner] = struct{}{}
+			combined = append(combined, owner)
+		}
+	}
+	return Rule{
+		Pattern: strings.Join(patterns, " | "),
+		Owners:  combined,
+		match:   func(string) bool { return true },
+	}, true
+}
+
+// Maintainers returns the user logins of owners who can approve changes,
+// in the order of their first appearance.
+// Teams, reviewers and emeritus owners are excluded.
+func (f *File) Maintainers() []string {
+	var maintainers []string
+	seen := make(map[string]struct{})
+	for _, rule := range f.Rules {
+		for _, owner := range rule.Owners {
+			switch owner.Role {
+			case RoleOwner, RoleApprover, RoleMaintainer:
+			default:
+				continue
+			}
+			if owner.IsTeam() {
+				continue
+			}
+			key := strings.ToLower(owner.Login)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			maintainers = append(maintainers, owner.Login)
+		}
+	}
+	return maintainers
+}
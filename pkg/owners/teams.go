@@ -0,0 +1,50 @@
+package owners
+
+import (
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Teams maps GitHub teams in `org/team` to their member logins.
+type Teams map[string][]string
+
+// ParseTeams parses a team file in YAML, which lists the members of the teams
+// referenced by ownership files. For example,
+//
+//	teams:
+//	  contoso/maintainers: [alice, bob]
+//	  contoso/docs: [carol]
+func ParseTeams(content []byte) (Teams, error) {
+	var file struct {
+		Teams map[string][]string `yaml:"teams"`
+	}
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return nil, err
+	}
+	teams := make(Teams, len(file.Teams))
+	for team, members := range file.Teams {
+		teams[strings.ToLower(strings.TrimPrefix(team, "@"))] = members
+	}
+	return teams, nil
+}
+
+// Logins returns the lowercase user logins of the owners, where teams are
+// expanded to their members. Emeritus owners and teams unknown to t are
+// skipped.
+func (t Teams) Logins(owners []Owner) []string {
+	var logins []string
+	for _, owner := range owners {
+		if owner.Role == RoleEmeritus {
+			continue
+		}
+		if !owner.IsTeam() {
+			logins = append(logins, strings.ToLower(owner.Login))
+			continue
+		}
+		for _, member := range t[strings.ToLower(owner.Login)] {
+			logins = append(logins, strings.ToLower(strings.TrimPrefix(member, "@")))
+		}
+	}
+	return logins
+}